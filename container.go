@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+func handleContainer() {
+	var opts config.ContainerOptions
+	fs := flag.NewFlagSet("container", flag.ExitOnError)
+	clearContainer := fs.Bool("clear", false, "open the alias on the host again")
+	fs.StringVar(&opts.Workdir, "workdir", "", "directory inside the container to open")
+	fs.BoolVar(&opts.Shell, "shell", false, "start a shell in the container instead of the editor")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Println("Error: usage is `gopen container alias [devcontainer|docker:NAME|podman:NAME]`")
+		os.Exit(1)
+	}
+	alias := args[0]
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	selected, err := cfg.Select([]string{alias}, "")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(args) == 1 && !*clearContainer {
+		current := selected[0].ContainerOptions
+		if current.Container == "" {
+			fmt.Println("none")
+			return
+		}
+		fmt.Println(current.Container)
+		if current.Workdir != "" {
+			fmt.Printf("  workdir: %v\n", current.Workdir)
+		}
+		if current.Shell {
+			fmt.Println("  opens a shell")
+		}
+		return
+	}
+
+	if len(args) == 2 {
+		opts.Container = args[1]
+	}
+
+	saved := updateConfig(func(cfg config.C) (config.C, error) {
+		cfg, err := cfg.SetContainerOptions(alias, opts)
+		if err != nil {
+			return cfg, fmt.Errorf("error: %v", err)
+		}
+		return cfg, nil
+	})
+	if !saved {
+		os.Exit(1)
+	}
+
+	if opts.Container == config.ContainerDevcontainer && !hasDevcontainer(selected[0].Path) {
+		fmt.Printf("Warning: no .devcontainer folder or .devcontainer.json found in %v\n", selected[0].Path)
+	}
+}
+
+// hasDevcontainer reports whether path has a devcontainer configuration in
+// one of the locations that the devcontainer CLI looks at.
+func hasDevcontainer(path string) bool {
+	for _, p := range []string{".devcontainer", ".devcontainer.json"} {
+		_, err := os.Stat(filepath.Join(path, p))
+		if err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+func handleDetach() {
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	switch len(os.Args) {
+	case 2:
+		mode := cfg.Detach
+		if mode == "" {
+			mode = config.DetachAuto
+		}
+		fmt.Println(mode)
+
+		for _, dirAlias := range cfg.DirAliases {
+			if dirAlias.Detach != "" {
+				fmt.Printf("  %v: %v\n", dirAlias.Alias, dirAlias.Detach)
+			}
+		}
+
+	case 3, 4:
+		saved := updateConfig(func(cfg config.C) (config.C, error) {
+			var err error
+			if len(os.Args) == 3 {
+				cfg, err = cfg.SetDetach(os.Args[2])
+			} else {
+				// `default` clears the alias' own mode
+				mode := os.Args[2]
+				if mode == "default" {
+					mode = ""
+				}
+				cfg, err = cfg.SetAliasDetach(os.Args[3], mode)
+			}
+			if err != nil {
+				return cfg, fmt.Errorf("error: %v", err)
+			}
+			return cfg, nil
+		})
+		if !saved {
+			os.Exit(1)
+		}
+
+	default:
+		fmt.Println("Too many arguments - exiting...")
+		os.Exit(1)
+	}
+}
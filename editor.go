@@ -0,0 +1,224 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// envFlags collects repeated `--env KEY=VALUE` flags.
+type envFlags map[string]string
+
+func (e envFlags) String() string {
+	return fmt.Sprint(map[string]string(e))
+}
+
+func (e envFlags) Set(s string) error {
+	k, v, found := strings.Cut(s, "=")
+	if !found || k == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", s)
+	}
+	e[k] = v
+	return nil
+}
+
+func handleEditor() {
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	if len(os.Args) < 3 {
+		if cfg.DefaultEditor == "" {
+			fmt.Println(cfg.EditorCmd)
+			return
+		}
+
+		editor, err := cfg.Editor("")
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			os.Exit(1)
+		}
+		fmt.Printf("%v (%v)\n", editor.Name, editor.CommandLine())
+		return
+	}
+
+	var change func(config.C) (config.C, error)
+	switch os.Args[2] {
+	case "--resolved":
+		if len(os.Args) > 4 {
+			fmt.Println("Error: usage is `gopen editor --resolved [alias]`")
+			os.Exit(1)
+		}
+		alias := ""
+		if len(os.Args) == 4 {
+			alias = os.Args[3]
+		}
+
+		editor, source, err := cfg.ResolveEditor(alias, "")
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			os.Exit(1)
+		}
+		fmt.Printf("%v (from %v)\n", editor.CommandLine(), source)
+		return
+
+	case "list":
+		for _, p := range cfg.Editors {
+			marker := " "
+			if p.Name == cfg.DefaultEditor {
+				marker = "*"
+			}
+			fmt.Printf("%v %v: %v\n", marker, p.Name, p.CommandLine())
+		}
+		return
+
+	case "add":
+		profile, err := editorProfile(os.Args[3:])
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			os.Exit(1)
+		}
+		change = func(cfg config.C) (config.C, error) {
+			return cfg.AddEditor(profile)
+		}
+
+	case "rule":
+		switch {
+		case len(os.Args) == 3:
+			for _, rule := range cfg.EditorRules {
+				fmt.Printf("%v: %v\n", rule.Match, rule.Editor)
+			}
+			return
+		case len(os.Args) == 6 && os.Args[3] == "add":
+			change = func(cfg config.C) (config.C, error) {
+				return cfg.AddEditorRule(config.EditorRule{Match: os.Args[4], Editor: os.Args[5]})
+			}
+		case len(os.Args) == 5 && os.Args[3] == "remove":
+			change = func(cfg config.C) (config.C, error) {
+				return cfg.RemoveEditorRule(os.Args[4])
+			}
+		default:
+			fmt.Println("Error: usage is `gopen editor rule [add pattern editor | remove pattern]`")
+			os.Exit(1)
+		}
+
+	case "use":
+		switch len(os.Args) {
+		case 3, 4:
+			profile := ""
+			if len(os.Args) == 4 {
+				profile = os.Args[3]
+			}
+			change = func(cfg config.C) (config.C, error) {
+				return cfg.UseEditor(profile)
+			}
+		case 5:
+			editor := os.Args[3]
+			if editor == "default" {
+				editor = ""
+			}
+			change = func(cfg config.C) (config.C, error) {
+				return cfg.SetAliasEditor(os.Args[4], editor)
+			}
+		default:
+			fmt.Println("Error: usage is `gopen editor use [profile [alias]]`")
+			os.Exit(1)
+		}
+
+	case "remove":
+		if len(os.Args) != 4 {
+			fmt.Println("Error: usage is `gopen editor remove profile`")
+			os.Exit(1)
+		}
+		change = func(cfg config.C) (config.C, error) {
+			return cfg.RemoveEditor(os.Args[3])
+		}
+
+	default:
+		change = func(cfg config.C) (config.C, error) {
+			cfg.EditorCmd = os.Args[2]
+			return cfg, nil
+		}
+	}
+
+	saved := updateConfig(func(cfg config.C) (config.C, error) {
+		cfg, err := change(cfg)
+		if err != nil {
+			return cfg, fmt.Errorf("error: %v", err)
+		}
+		return cfg, nil
+	})
+	if !saved {
+		os.Exit(1)
+	}
+}
+
+func handleWhichEditor() {
+	if len(os.Args) != 3 {
+		fmt.Println("Error: must provide one alias to 'which-editor' command")
+		os.Exit(1)
+	}
+	alias := os.Args[2]
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	editor, source, err := cfg.ResolveEditor(alias, "")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		os.Exit(1)
+	}
+
+	fmt.Printf("`%v` opens with %v\n", alias, editor.CommandLine())
+	switch source {
+	case config.EditorFromAlias:
+		fmt.Println("It's set for the alias (see `gopen editor use`)")
+	case config.EditorFromRule:
+		selected, _ := cfg.Select([]string{alias}, "")
+		rule, matches, _ := cfg.MatchEditorRule(selected[0], config.DefaultFS)
+		fmt.Printf("The rule `%v` matched %v\n", rule.Match, strings.Join(matches, ", "))
+	case config.EditorFromConfig:
+		fmt.Println("No rule matched, so the default editor of the config is used")
+	default:
+		fmt.Printf("No editor is configured, so %v is used\n", source)
+	}
+}
+
+// editorProfile returns the profile described by args (`name 'command args'`
+// and flags).
+func editorProfile(args []string) (config.EditorProfile, error) {
+	env := envFlags{}
+	fs := flag.NewFlagSet("editor add", flag.ExitOnError)
+	detach := fs.String("detach", "", "detach mode of the profile (auto, always, or never)")
+	fs.Var(env, "env", "environment variable to set, as KEY=VALUE (repeatable)")
+	args = parseArgs(fs, args)
+
+	if len(args) != 2 {
+		return config.EditorProfile{}, fmt.Errorf("usage is `gopen editor add name 'command args'`")
+	}
+
+	fields := strings.Fields(args[1])
+	if len(fields) == 0 {
+		return config.EditorProfile{}, fmt.Errorf("editor profiles need a command")
+	}
+
+	profile := config.EditorProfile{
+		Name:    args[0],
+		Command: fields[0],
+		Args:    fields[1:],
+		Detach:  *detach,
+	}
+	if len(env) > 0 {
+		profile.Env = env
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/waseem-medhat/gopen/internal/export"
+)
+
+func handleExport() {
+	var opts export.Options
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "output format ("+strings.Join(export.Formats, ", ")+")")
+	output := fs.String("output", "", "file to write to instead of stdout")
+	fs.StringVar(&opts.Root, "root", "", "projects directory to templatize as $ROOT in bundles")
+	fs.BoolVar(&opts.Strip, "strip", false, "leave paths out of bundles entirely")
+
+	args := parseArgs(fs, os.Args[2:])
+	if *format == "" || len(args) > 0 {
+		fmt.Println("Error: usage is `gopen export --format fmt`")
+		os.Exit(1)
+	}
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	err = export.Render(w, cfg, *format, opts)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		os.Exit(1)
+	}
+}
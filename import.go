@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/importer"
+)
+
+func handleImport() {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	from := fs.String("from", "", "tool to import from ("+strings.Join(importer.Sources, ", ")+", or bundle)")
+	root := fs.String("root", "", "projects directory for bundles with templatized or stripped paths")
+	onConflict := fs.String("on-conflict", importer.OnConflictSkip, "what to do with existing aliases (skip, overwrite, rename)")
+	limit := fs.Int("limit", 0, "only import the n highest-ranked entries (0 imports all)")
+	yes := fs.Bool("yes", false, "write the aliases without asking for confirmation")
+
+	args := parseArgs(fs, os.Args[2:])
+	if *from == "" || len(args) != 1 {
+		fmt.Println("Error: usage is `gopen import --from tool file`")
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		os.Exit(1)
+	}
+
+	var entries []importer.Entry
+	if *from == "bundle" {
+		entries, err = importer.ParseBundle(data, *root)
+	} else {
+		entries, err = importer.Parse(*from, data)
+	}
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		os.Exit(1)
+	}
+
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[:*limit]
+	}
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	actions, err := importer.Plan(cfg, entries, *onConflict)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	changes := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tALIAS\tPATH\tNOTE")
+	for _, a := range actions {
+		if a.Op != importer.OpSkip {
+			changes++
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", a.Op, a.Alias, a.Path, a.Reason)
+	}
+	w.Flush()
+
+	if changes == 0 {
+		fmt.Println("\nNothing to import")
+		return
+	}
+
+	if !*yes && !confirm(fmt.Sprintf("\nImport %d alias(es)?", changes)) {
+		fmt.Println("Cancelled - no aliases were imported")
+		return
+	}
+
+	// The config may have changed while waiting for the confirmation, so the
+	// plan is made again on the config being updated, and only applied if it
+	// is still the one that was shown.
+	err = store.Update(func(cfg config.C) (config.C, error) {
+		current, err := importer.Plan(cfg, entries, *onConflict)
+		if err != nil {
+			return cfg, err
+		}
+		if !reflect.DeepEqual(current, actions) {
+			return cfg, errors.New("Error: the config changed meanwhile - run the import again to review the new plan")
+		}
+
+		return importer.Apply(cfg, actions)
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d alias(es)\n", changes)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+func handleInit() {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "don't ask anything: use the first editor found and add all scanned projects")
+	scanRoot := fs.String("scan", "", "directory to scan for projects")
+	depth := fs.Int("depth", 2, "how many directory levels below the scanned directory to search")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) > 0 {
+		fmt.Println("Too many arguments - exiting...")
+		os.Exit(1)
+	}
+
+	_, err := os.Stat(store.Path())
+	if err == nil {
+		fmt.Println(fmt.Errorf("error: %v", os.ErrExist))
+		return
+	}
+
+	var cfg config.C
+	cfg.EditorCmd = pickEditor(*yes)
+	if cfg.EditorCmd == "" {
+		fmt.Println("No editor found - set one later with `gopen editor youreditor`")
+	} else {
+		fmt.Printf("Using editor `%v`\n", cfg.EditorCmd)
+	}
+
+	root := *scanRoot
+	if root == "" && !*yes {
+		root = prompt("Scan a directory for projects? Enter its path (leave empty to skip):", "")
+	}
+	if root != "" {
+		root, err = config.ExpandHome(root)
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			os.Exit(1)
+		}
+
+		var added int
+		cfg, added, err = scanInto(cfg, root, *depth, *yes)
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			os.Exit(1)
+		}
+		if added > 0 {
+			fmt.Printf("Added %d alias(es)\n", added)
+		}
+	}
+
+	err = store.Init(cfg)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	fmt.Printf("Config written to %v\n", store.Path())
+}
+
+// pickEditor returns the editor command to start with: one of the editors
+// found on PATH, falling back to $VISUAL or $EDITOR. Unless yes is set, the
+// user can pick another one or type any command.
+func pickEditor(yes bool) string {
+	editors := config.DetectEditors()
+	if env := config.EnvEditor(); env != "" && !slices.Contains(editors, env) {
+		editors = append(editors, env)
+	}
+
+	if yes {
+		if len(editors) == 0 {
+			return ""
+		}
+		return editors[0]
+	}
+
+	if len(editors) == 0 {
+		return prompt("No editors found. Which command opens your editor?", "")
+	}
+
+	fmt.Println("Editors found:")
+	for i, editor := range editors {
+		fmt.Printf("  %d) %v\n", i+1, editor)
+	}
+
+	answer := prompt("Which one do you want to use? Enter a number or a command", "1")
+	n, err := strconv.Atoi(answer)
+	if err == nil && n >= 1 && n <= len(editors) {
+		return editors[n-1]
+	}
+	return answer
+}
@@ -6,32 +6,101 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
-
-	git "github.com/go-git/go-git/v5"
 )
 
 // C is the struct representation of Gopen config.
 type C struct {
-	EditorCmd       string     `json:"editorCmd"`
-	CustomBehaviour bool       `json:"customBehaviour"`
-	DirAliases      []DirAlias `json:"aliases"`
+	EditorCmd       string          `json:"editorCmd"`
+	CustomBehaviour bool            `json:"customBehaviour"`
+	Multiplexer     string          `json:"multiplexer,omitempty"`
+	Detach          string          `json:"detach,omitempty"`
+	Editors         []EditorProfile `json:"editors,omitempty"`
+	DefaultEditor   string          `json:"defaultEditor,omitempty"`
+	EditorRules     []EditorRule    `json:"editorRules,omitempty"`
+	DirAliases      []DirAlias      `json:"aliases"`
 }
 
 // DirAlias is the struct type for the directory aliases where each struct
-// contains the alias and the path it corresponds to.
+// contains the alias and the path it corresponds to. Despite the name, the
+// path can also point to a single file. If Host is set, the path is on that
+// SSH host instead of the local machine, and if Container is set, the alias
+// is opened inside that container.
 type DirAlias struct {
-	Alias   string `json:"alias"`
-	Path    string `json:"path"`
-	GitRepo string `json:"git_repo"`
+	Alias   string   `json:"alias"`
+	Path    string   `json:"path"`
+	Kind    string   `json:"kind,omitempty"`
+	Host    string   `json:"host,omitempty"`
+	GitRepo string   `json:"git_repo,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Detach  string   `json:"detach,omitempty"`
+	Editor  string   `json:"editor,omitempty"`
+	GitOptions
+	ContainerOptions
+	MuxOptions
+}
+
+// Kinds of paths an alias can point to, as recorded in DirAlias.Kind. An
+// empty kind means that the path didn't exist when the alias was added.
+const (
+	KindDir  = "dir"
+	KindFile = "file"
+)
+
+// reserved holds the names that can't be used as aliases because they are
+// taken by the commands of `gopen`, including their one-letter shorthands.
+// It's kept here rather than in the main package so that aliases saved
+// through pkg/gopen are checked too.
+var reserved = []string{
+	"help", "h",
+	"init", "i",
+	"editor", "e",
+	"which-editor",
+	"alias", "a",
+	"git", "g",
+	"container",
+	"mux",
+	"detach",
+	"layout",
+	"remove", "r",
+	"rename",
+	"move",
+	"custom", "c",
+	"open",
+	"sync",
+	"status",
+	"pull",
+	"tag",
+	"scan",
+	"import",
+	"export",
+	"doctor",
+}
+
+// IsReserved reports whether name is taken by a `gopen` command and can't be
+// used as an alias.
+func IsReserved(name string) bool {
+	return slices.Contains(reserved, name)
+}
+
+// Reserved returns the names taken by `gopen` commands. The command table of
+// the main package is tested against it.
+func Reserved() []string {
+	return slices.Clone(reserved)
 }
 
 // Init checks if the config file exists in configPath. If not, creates an
 // empty config file. configDir will also be created if it doesn't exist.
 func Init(configDir string, configPath string) error {
+	return InitWith(C{}, configDir, configPath)
+}
+
+// InitWith is like Init but writes cfg instead of an empty config.
+func InitWith(cfg C, configDir string, configPath string) error {
 	_, err := os.Stat(configPath)
 	if err == nil {
 		return os.ErrExist
@@ -47,8 +116,7 @@ func Init(configDir string, configPath string) error {
 		return err
 	}
 
-	emptyConfig := C{}
-	err = Write(emptyConfig, configPath)
+	err = Write(cfg, configPath)
 	if err != nil {
 		return err
 	}
@@ -56,19 +124,81 @@ func Init(configDir string, configPath string) error {
 	return err
 }
 
-// Write writes config to configPath (will OVERWRITE if file already exists)
+// Write writes config to configPath (will OVERWRITE if file already exists).
+// The file is replaced in one step, so readers never see it half written. If
+// configPath is a symlink, the file it points to is replaced instead.
 func Write(config C, configPath string) error {
 	jsonFile, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	err = os.WriteFile(configPath, jsonFile, 0644)
+	if real, err := filepath.EvalSymlinks(configPath); err == nil {
+		configPath = real
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(configPath), ".gopen-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(jsonFile)
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
 		return err
 	}
 
-	return err
+	return os.Rename(tmp.Name(), configPath)
+}
+
+// Update reads configPath, changes it with fn, and writes the result unless
+// fn fails. Other updates of the same file, in this process or others, wait
+// for it to finish, so none of their changes are lost.
+func Update(configPath string, fn func(C) (C, error)) error {
+	return withLock(configPath, func() error {
+		cfg, err := Read(configPath)
+		if err != nil {
+			return err
+		}
+
+		cfg, err = fn(cfg)
+		if err != nil {
+			return err
+		}
+
+		return Write(cfg, configPath)
+	})
+}
+
+// Replace is like Write, but waits for the updates of configPath that are
+// running to finish first, like Update does.
+func Replace(config C, configPath string) error {
+	return withLock(configPath, func() error {
+		return Write(config, configPath)
+	})
+}
+
+// withLock runs fn while holding the lock file of configPath.
+func withLock(configPath string, fn func() error) error {
+	lock, err := os.OpenFile(configPath+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	err = lockFile(lock)
+	if err != nil {
+		return err
+	}
+	defer unlockFile(lock)
+
+	return fn()
 }
 
 // Read reads the configPath file and returns a Config struct
@@ -100,7 +230,7 @@ func (cfg C) ListAliases() []string {
 
 	var fmtAliases []string
 	for _, dirAlias := range cfg.DirAliases {
-		fmtAlias := fmt.Sprintf("%*s: %s", width, dirAlias.Alias, dirAlias.Path)
+		fmtAlias := fmt.Sprintf("%*s: %s", width, dirAlias.Alias, dirAlias.Location())
 		fmtAliases = append(fmtAliases, fmtAlias)
 	}
 
@@ -109,112 +239,524 @@ func (cfg C) ListAliases() []string {
 
 // AddAlias takes a config, a new alias, and its path, then it returns a new
 // config struct with the newly added alias. If the alias already exists, the
-// function will overwrite it. It also ensures that no alias matches Gopen
-// commands like `alias` or `init`.
+// function will only change its path and keep all its other fields. It also
+// ensures that no alias matches Gopen commands like `alias` or `init`. If the
+// path is a git repo with an `origin` remote and the alias has no git repo
+// yet, the remote's URL is recorded as the alias' git repo. Whether the path
+// is a directory or a file is recorded as its kind. A remote alias added again
+// with AddAlias becomes local.
 func (cfg C) AddAlias(alias string, path string) (C, error) {
+	err := validateAlias(alias)
+	if err != nil {
+		return cfg, err
+	}
+
+	newPath, err := absPath(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	// Detection is best-effort; a path that isn't a readable repo simply
+	// gets no remote
+	remote, _ := DetectRemote(newPath)
+	kind := pathKind(newPath)
+
+	if cfg.hasAlias(alias) {
+		return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+			d.Path = newPath
+			d.Kind = kind
+			d.Host = ""
+			if d.GitRepo == "" {
+				d.GitRepo = remote
+			}
+			return nil
+		})
+	}
+
+	newDirAlias := DirAlias{Alias: alias, Path: newPath, Kind: kind, GitRepo: remote}
 	newCfg := cfg
+	newCfg.DirAliases = append(slices.Clip(cfg.DirAliases), newDirAlias)
+	return newCfg, nil
+}
+
+// pathKind returns the kind of path, or an empty string if it doesn't exist.
+func pathKind(path string) string {
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return ""
+	case info.IsDir():
+		return KindDir
+	default:
+		return KindFile
+	}
+}
+
+// UpdateAlias is the single entry point for changing an existing alias. It
+// calls update with a copy of the alias and, unless update fails, returns a
+// new config holding the updated copy. Fields that update doesn't touch,
+// including the rest of the config, are kept as they are, and cfg itself is
+// never modified.
+func (cfg C) UpdateAlias(alias string, update func(*DirAlias) error) (C, error) {
+	for i, dirAlias := range cfg.DirAliases {
+		if dirAlias.Alias != alias {
+			continue
+		}
+
+		err := update(&dirAlias)
+		if err != nil {
+			return cfg, err
+		}
+
+		newCfg := cfg
+		newCfg.DirAliases = slices.Clone(cfg.DirAliases)
+		newCfg.DirAliases[i] = dirAlias
+		return newCfg, nil
+	}
+
+	return cfg, fmt.Errorf("alias doesn't exist")
+}
+
+// RemoveAlias returns a new config without alias, keeping everything else.
+func (cfg C) RemoveAlias(alias string) (C, error) {
+	if !cfg.hasAlias(alias) {
+		return cfg, fmt.Errorf("alias doesn't exist")
+	}
+
+	newCfg := cfg
+	newCfg.DirAliases = slices.DeleteFunc(slices.Clone(cfg.DirAliases), func(d DirAlias) bool {
+		return d.Alias == alias
+	})
+	return newCfg, nil
+}
+
+// RenameAlias renames oldAlias to newAlias, keeping all its other fields. It
+// fails if newAlias already exists or is reserved.
+func (cfg C) RenameAlias(oldAlias string, newAlias string) (C, error) {
+	err := validateAlias(newAlias)
+	if err != nil {
+		return cfg, err
+	}
+
+	if cfg.hasAlias(newAlias) {
+		return cfg, fmt.Errorf("alias `%v` already exists", newAlias)
+	}
+
+	return cfg.UpdateAlias(oldAlias, func(d *DirAlias) error {
+		d.Alias = newAlias
+		return nil
+	})
+}
+
+// MoveAlias points alias to path, keeping all its other fields except the
+// kind, which is recorded again for local paths. It only changes the config;
+// MoveAliasOnDisk also moves the directory. Paths of remote aliases are used
+// as-is, like in AddRemoteAlias.
+func (cfg C) MoveAlias(alias string, path string) (C, error) {
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		if d.IsRemote() {
+			err := validateRemote(d.Host, path)
+			if err != nil {
+				return err
+			}
+			d.Path = path
+			return nil
+		}
+
+		newPath, err := absPath(path)
+		if err != nil {
+			return err
+		}
+		d.Path = newPath
+		d.Kind = pathKind(newPath)
+		return nil
+	})
+}
+
+// MoveAliasOnDisk moves the directory or file of a local alias to path and
+// then points the alias to it, so that its kind is recorded from the moved
+// path. It fails without moving anything if path already exists.
+func (cfg C) MoveAliasOnDisk(alias string, path string) (C, error) {
+	selected, err := cfg.Select([]string{alias}, "")
+	if err != nil {
+		return cfg, err
+	}
+	if selected[0].IsRemote() {
+		return cfg, errors.New("moving on disk isn't supported for remote aliases")
+	}
+
+	newPath, err := absPath(path)
+	if err != nil {
+		return cfg, err
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return cfg, fmt.Errorf("%v already exists", newPath)
+	}
+
+	err = os.Rename(selected[0].Path, newPath)
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg.MoveAlias(alias, newPath)
+}
+
+func (cfg C) hasAlias(alias string) bool {
+	return slices.ContainsFunc(cfg.DirAliases, func(d DirAlias) bool {
+		return d.Alias == alias
+	})
+}
+
+// validateAlias ensures that alias isn't empty and doesn't match a Gopen
+// command.
+func validateAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("Error: alias can't be empty")
+	}
 
-	reserved := []string{"a", "alias", "e", "editor", "h", "help", "i", "init", "g", "git"}
 	for _, r := range reserved {
 		if r == alias {
-			err := fmt.Errorf("Error: `%v` is reserved and can't be used as an alias", alias)
-			return newCfg, err
+			return fmt.Errorf("Error: `%v` is reserved and can't be used as an alias", alias)
 		}
 	}
 
+	return nil
+}
+
+// CheckAlias validates an alias that is built by hand rather than through
+// the methods of C, like the ones put in a pkg/gopen/store.Store, and returns
+// it with its local path made absolute. Reserved names, unknown kinds, and
+// invalid options are rejected like the methods of C would.
+func CheckAlias(d DirAlias) (DirAlias, error) {
+	err := validateAlias(d.Alias)
+	if err != nil {
+		return d, err
+	}
+
+	if d.IsRemote() {
+		err = validateRemote(d.Host, d.Path)
+	} else {
+		d.Path, err = absPath(d.Path)
+	}
+	if err != nil {
+		return d, err
+	}
+
+	if !slices.Contains([]string{"", KindDir, KindFile}, d.Kind) {
+		return d, fmt.Errorf("invalid kind `%v` (expected %v or %v)", d.Kind, KindDir, KindFile)
+	}
+
+	// The setters validate their options against an alias of their own
+	check := C{DirAliases: []DirAlias{{Alias: d.Alias, Host: d.Host}}}
+	_, err = check.SetGitOptions(d.Alias, d.GitOptions)
+	if err == nil {
+		_, err = check.SetContainerOptions(d.Alias, d.ContainerOptions)
+	}
+	if err == nil {
+		_, err = check.SetMuxOptions(d.Alias, d.MuxOptions)
+	}
+	if err == nil {
+		_, err = check.SetAliasDetach(d.Alias, d.Detach)
+	}
+	return d, err
+}
+
+// absPath returns the absolute version of path as stored in aliases.
+func absPath(path string) (string, error) {
 	// If the path is ".", then we want to use the current directory
 	// instead of the literal "."
 	if path == "." {
 		path = "./"
 	}
-	newPath, err := filepath.Abs(path)
+
+	return filepath.Abs(path)
+}
+
+// SuggestAlias derives an alias from the last element of path. The result is
+// lowercased, stripped of characters that are awkward to type in a shell, and
+// suffixed with a number if it's reserved or already in taken.
+func SuggestAlias(path string, taken []string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(path)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+
+	base := strings.Trim(b.String(), ".-")
+	if base == "" {
+		base = "project"
+	}
+
+	return UniqueAlias(base, taken)
+}
+
+// UniqueAlias returns alias, suffixed with a number if it's reserved or
+// already in taken.
+func UniqueAlias(alias string, taken []string) string {
+	unique := alias
+	for i := 2; slices.Contains(reserved, unique) || slices.Contains(taken, unique); i++ {
+		unique = fmt.Sprintf("%v-%d", alias, i)
+	}
+
+	return unique
+}
+
+// SetGitRepo sets the remote git repo of alias, keeping its clone options.
+func (cfg C) SetGitRepo(alias string, repo string) (C, error) {
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		d.GitRepo = repo
+		return nil
+	})
+}
+
+// DetectGitRepo sets the git repo of alias to the `origin` remote of its
+// path and returns the detected URL. The config is unchanged if no remote is
+// found.
+func (cfg C) DetectGitRepo(alias string) (C, string, error) {
+	var remote string
+	newCfg, err := cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		var err error
+		remote, err = DetectRemote(d.Path)
+		if err != nil {
+			return err
+		}
+		if remote == "" {
+			return fmt.Errorf("no `origin` remote found in %v", d.Path)
+		}
+
+		d.GitRepo = remote
+		return nil
+	})
+
+	return newCfg, remote, err
+}
+
+// SetGitOptions replaces the clone options of alias. The auth method is
+// validated before anything is changed.
+func (cfg C) SetGitOptions(alias string, opts GitOptions) (C, error) {
+	err := validateGitAuth(opts.Auth)
 	if err != nil {
-		return newCfg, err
+		return cfg, err
 	}
 
-	newDirAlias := DirAlias{Alias: alias, Path: newPath}
+	if opts.Depth < 0 {
+		return cfg, fmt.Errorf("clone depth can't be negative")
+	}
 
-	for i, dirAlias := range cfg.DirAliases {
-		if dirAlias.Alias == alias {
-			newCfg.DirAliases[i] = newDirAlias
-			return newCfg, err
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		d.GitOptions = opts
+		return nil
+	})
+}
+
+// SetTags replaces the tags of alias. Duplicate and empty tags are dropped.
+func (cfg C) SetTags(alias string, tags []string) (C, error) {
+	var newTags []string
+	for _, tag := range tags {
+		if tag != "" && !slices.Contains(newTags, tag) {
+			newTags = append(newTags, tag)
 		}
 	}
 
-	newCfg.DirAliases = append(newCfg.DirAliases, newDirAlias)
-	return newCfg, err
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		d.Tags = newTags
+		return nil
+	})
 }
 
-func (cfg C) SetGitRepo(alias string, repo string) (C, error) {
-	for i, dirAlias := range cfg.DirAliases {
-		if dirAlias.Alias == alias {
-			newDirAlias := DirAlias{
-				Alias:   dirAlias.Alias,
-				Path:    dirAlias.Path,
-				GitRepo: repo,
-			}
+// Problem is an issue with an alias found by Doctor, along with a hint on how
+// to fix it.
+type Problem struct {
+	Alias   string
+	Message string
+	Hint    string
+}
+
+// Doctor checks all aliases for problems: aliases shadowed by commands and
+// paths whose kind (file or directory) changed since the alias was added.
+func (cfg C) Doctor() []Problem {
+	var problems []Problem
+	for _, alias := range cfg.ShadowedAliases() {
+		problems = append(problems, Problem{
+			Alias:   alias,
+			Message: fmt.Sprintf("shadowed by the `%v` command", alias),
+			Hint:    fmt.Sprintf("open it with `gopen open %v` or rename it with `gopen rename %v newname`", alias, alias),
+		})
+	}
+
+	for _, dirAlias := range cfg.DirAliases {
+		if dirAlias.IsRemote() {
+			continue
+		}
 
-			cfg.DirAliases[i] = newDirAlias
-			return cfg, nil
+		kind := pathKind(dirAlias.Path)
+		if dirAlias.Kind == "" || kind == "" || kind == dirAlias.Kind {
+			continue
 		}
+
+		problems = append(problems, Problem{
+			Alias:   dirAlias.Alias,
+			Message: fmt.Sprintf("was added as a %v but %v is now a %v", dirAlias.Kind, dirAlias.Path, kind),
+			Hint:    fmt.Sprintf("re-add it with `gopen alias %v %v` if that's intended", dirAlias.Alias, dirAlias.Path),
+		})
 	}
 
-	return cfg, fmt.Errorf("alias doesn't exist")
+	return problems
 }
 
-// Gopen uses the Config struct to find the path corresponding to targetAlias
-// and executes the editor command with the target path as the working
-// directory
-func (cfg C) Gopen(targetAlias string) error {
-	var targetPath string
-	var targetRepo string
+// ShadowedAliases returns the aliases that can't be opened with `gopen foo`
+// because they match a reserved name. This happens to aliases added before the
+// command was, or before reserved names were checked consistently.
+func (cfg C) ShadowedAliases() []string {
+	var shadowed []string
 	for _, dirAlias := range cfg.DirAliases {
-		if targetAlias == dirAlias.Alias {
-			targetPath = dirAlias.Path
-			targetRepo = dirAlias.GitRepo
-			break
+		if slices.Contains(reserved, dirAlias.Alias) {
+			shadowed = append(shadowed, dirAlias.Alias)
 		}
 	}
 
-	if targetPath == "" {
-		return errors.New("Invalid command or non-existent alias\nRun `gopen help` for info")
+	return shadowed
+}
+
+// Select returns the aliases named in aliases plus the ones tagged with tag,
+// keeping the order of the config. If neither is given, all aliases are
+// returned.
+func (cfg C) Select(aliases []string, tag string) ([]DirAlias, error) {
+	if len(aliases) == 0 && tag == "" {
+		return cfg.DirAliases, nil
 	}
 
-	editorCmd := strings.Split(cfg.EditorCmd, " ")
+	for _, alias := range aliases {
+		found := false
+		for _, dirAlias := range cfg.DirAliases {
+			if dirAlias.Alias == alias {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("alias `%v` doesn't exist", alias)
+		}
+	}
 
-	_, err := os.Stat(targetPath)
-	if os.IsNotExist(err) && targetRepo != "" {
-		fmt.Printf("dir %v not found\ntrying to clone %v\n", targetPath, targetRepo)
-		_, err = git.PlainClone(targetPath, false, &git.CloneOptions{
-			URL:      targetRepo,
-			Progress: os.Stdout,
-		})
+	var selected []DirAlias
+	for _, dirAlias := range cfg.DirAliases {
+		if slices.Contains(aliases, dirAlias.Alias) || (tag != "" && slices.Contains(dirAlias.Tags, tag)) {
+			selected = append(selected, dirAlias)
+		}
 	}
-	if err != nil {
-		return err
+
+	return selected, nil
+}
+
+// MissingRepos returns the aliases whose path doesn't exist but have a git
+// repo that it can be cloned from. Paths of remote aliases are checked on
+// their hosts, and hosts that can't be reached are reported in unreachable.
+func (cfg C) MissingRepos() (missing []DirAlias, unreachable []error) {
+	for _, dirAlias := range cfg.DirAliases {
+		if dirAlias.GitRepo == "" {
+			continue
+		}
+
+		if dirAlias.IsRemote() {
+			exists, err := dirAlias.RemoteExists(DefaultRunner)
+			if err != nil {
+				unreachable = append(unreachable, fmt.Errorf("%v: %v", dirAlias.Alias, err))
+			} else if !exists {
+				missing = append(missing, dirAlias)
+			}
+			continue
+		}
+
+		_, err := os.Stat(dirAlias.Path)
+		if os.IsNotExist(err) {
+			missing = append(missing, dirAlias)
+		}
 	}
 
-	err = os.Chdir(targetPath)
-	if err != nil {
-		return err
+	return missing, unreachable
+}
+
+// ExpandHome replaces a leading `~` in path with the user's home directory.
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
 	}
 
-	var cmd *exec.Cmd
-	// CustomBehaviour lets the user open the target path in a new buffer
-	if cfg.CustomBehaviour {
-		cmd = exec.Command(editorCmd[0], editorCmd[1:]...)
-	} else {
-		cmd = exec.Command(editorCmd[0], targetPath)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
 	}
 
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	return filepath.Join(home, path[1:]), nil
+}
 
-	err = cmd.Run()
+// Gopen uses the Config struct to find the path corresponding to targetAlias
+// and executes the editor command with the target path as the working
+// directory. If the target path is a file, its parent directory is used as
+// the working directory and the file is passed to the editor.
+func (cfg C) Gopen(targetAlias string) error {
+	return cfg.GopenWith(targetAlias, OpenOptions{})
+}
+
+// OpenOptions tweak how GopenWith opens an alias.
+type OpenOptions struct {
+	// Editor is the editor profile (or editor command) to use instead of
+	// the default one, as accepted by C.Editor
+	Editor string
+	// Runner launches the editor, and any containers or multiplexer
+	// sessions; DefaultRunner is used if it's nil
+	Runner Runner
+	// FS is where the alias' path is looked up; DefaultFS is used if it's
+	// nil
+	FS FS
+	// Cloner clones missing repos; if it's nil, they're cloned like with
+	// DefaultCloner, with the ssh commands of remote clones going through
+	// Runner
+	Cloner Cloner
+	// Stdin, Stdout, and Stderr are the streams of the editor, and Stderr
+	// also gets the output of the commands that prepare it. The streams of
+	// the process are used if they're nil.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Progress gets the progress of clones, and can be nil
+	Progress io.Writer
+}
+
+// withDefaults returns opts with the defaults in place of nil fields.
+func (opts OpenOptions) withDefaults() OpenOptions {
+	if opts.Runner == nil {
+		opts.Runner = DefaultRunner
+	}
+	if opts.FS == nil {
+		opts.FS = DefaultFS
+	}
+	if opts.Cloner == nil {
+		opts.Cloner = gitCloner{runner: opts.Runner}
+	}
+	if opts.Stdin == nil {
+		opts.Stdin = os.Stdin
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	return opts
+}
+
+// GopenWith works like Gopen but with the given options.
+func (cfg C) GopenWith(targetAlias string, opts OpenOptions) error {
+	plan, err := cfg.Plan(targetAlias, opts)
 	if err != nil {
 		return err
 	}
 
-	return err
+	return plan.Exec(opts)
 }
@@ -3,6 +3,7 @@ package config_test
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -28,6 +29,25 @@ func TestInitConfigCreatesNewFile(t *testing.T) {
 	}
 }
 
+func TestInitWithWritesConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "gopen", "gopen.json")
+
+	cfg := config.C{EditorCmd: "hx"}
+	err := config.InitWith(cfg, filepath.Dir(configPath), configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := config.Read(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.EditorCmd != "hx" {
+		t.Errorf("Expected editor command hx, but got %q", actual.EditorCmd)
+	}
+}
+
 func TestInitConfigReturnsErrorIfFileExists(t *testing.T) {
 	dir, err := os.MkdirTemp("", "test")
 	if err != nil {
@@ -214,6 +234,42 @@ func TestWriteConfig(t *testing.T) {
 	}
 }
 
+func TestWriteFollowsSymlink(t *testing.T) {
+	dotfiles := t.TempDir()
+	real := filepath.Join(dotfiles, "gopen.json")
+	err := os.WriteFile(real, []byte("{}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(t.TempDir(), "gopen.json")
+	err = os.Symlink(real, link)
+	if err != nil {
+		t.Skip("symlinks aren't supported here:", err)
+	}
+
+	err = config.Write(config.C{EditorCmd: "vim"}, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Expected %v to still be a symlink but got mode %v", link, info.Mode())
+	}
+
+	cfg, err := config.Read(real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.EditorCmd != "vim" {
+		t.Errorf("Expected the linked file to be written but got editor %q", cfg.EditorCmd)
+	}
+}
+
 func TestList(t *testing.T) {
 	cfg := config.C{DirAliases: []config.DirAlias{}}
 	result := cfg.ListAliases()
@@ -295,3 +351,246 @@ func TestAdd(t *testing.T) {
 		t.Errorf("Expected %q, but got %q", expectedError, err.Error())
 	}
 }
+
+func TestMissingRepos(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "present", Path: dir, GitRepo: "git@example.com:present.git"},
+			{Alias: "missing", Path: dir + "/missing", GitRepo: "git@example.com:missing.git"},
+			{Alias: "norepo", Path: dir + "/norepo"},
+		},
+	}
+
+	missing, unreachable := cfg.MissingRepos()
+	if len(unreachable) > 0 {
+		t.Errorf("Expected no unreachable hosts, but got %v", unreachable)
+	}
+	if len(missing) != 1 || missing[0].Alias != "missing" {
+		t.Errorf("Expected only `missing` to be returned, but got %v", missing)
+	}
+}
+
+func TestTagsAndSelect(t *testing.T) {
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "a", Path: "/path/to/a"},
+			{Alias: "b", Path: "/path/to/b"},
+			{Alias: "c", Path: "/path/to/c"},
+		},
+	}
+
+	cfg, err := cfg.SetTags("c", []string{"work", "", "work", "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.DirAliases[2].Tags, []string{"work", "go"}) {
+		t.Errorf("Expected tags [work go], but got %v", cfg.DirAliases[2].Tags)
+	}
+
+	selected, err := cfg.Select([]string{"a"}, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].Alias != "a" || selected[1].Alias != "c" {
+		t.Errorf("Expected aliases a and c, but got %v", selected)
+	}
+
+	selected, err = cfg.Select(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 3 {
+		t.Errorf("Expected all aliases, but got %v", selected)
+	}
+
+	_, err = cfg.Select([]string{"nope"}, "")
+	if err == nil {
+		t.Error("Expected an error for a missing alias, but got nil")
+	}
+}
+
+func TestSuggestAlias(t *testing.T) {
+	cases := []struct {
+		path     string
+		taken    []string
+		expected string
+	}{
+		{"/code/My Project", nil, "my-project"},
+		{"/code/api", []string{"api"}, "api-2"},
+		{"/code/api", []string{"api", "api-2"}, "api-3"},
+		{"/code/init", nil, "init-2"},
+		{"/code/.dotfiles", nil, "dotfiles"},
+		{"/code/日本", nil, "project"},
+	}
+
+	for _, c := range cases {
+		actual := config.SuggestAlias(c.path, c.taken)
+		if actual != c.expected {
+			t.Errorf("SuggestAlias(%q, %v): expected %q, but got %q", c.path, c.taken, c.expected, actual)
+		}
+	}
+}
+
+func TestRenameAndMoveKeepFields(t *testing.T) {
+	newCfg := func() config.C {
+		return config.C{
+			DirAliases: []config.DirAlias{
+				{
+					Alias:      "proj",
+					Path:       "/path/to/proj",
+					GitRepo:    "git@example.com:proj.git",
+					Tags:       []string{"work"},
+					GitOptions: config.GitOptions{Branch: "dev", Depth: 1},
+				},
+				{Alias: "other", Path: "/path/to/other"},
+			},
+		}
+	}
+
+	cfg, err := newCfg().RenameAlias("proj", "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := newCfg().DirAliases[0]
+	expected.Alias = "renamed"
+	if !reflect.DeepEqual(cfg.DirAliases[0], expected) {
+		t.Errorf("Expected %v, but got %v", expected, cfg.DirAliases[0])
+	}
+
+	cfg, err = newCfg().MoveAlias("proj", "/new/path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = newCfg().DirAliases[0]
+	expected.Path = "/new/path"
+	if !reflect.DeepEqual(cfg.DirAliases[0], expected) {
+		t.Errorf("Expected %v, but got %v", expected, cfg.DirAliases[0])
+	}
+
+	_, err = newCfg().RenameAlias("proj", "other")
+	if err == nil {
+		t.Error("Expected an error when renaming to an existing alias, but got nil")
+	}
+
+	_, err = newCfg().RenameAlias("proj", "alias")
+	if err == nil {
+		t.Error("Expected an error when renaming to a reserved alias, but got nil")
+	}
+
+	_, err = newCfg().RenameAlias("nope", "new")
+	if err == nil {
+		t.Error("Expected an error when renaming a missing alias, but got nil")
+	}
+
+	_, err = newCfg().MoveAlias("nope", "/new/path")
+	if err == nil {
+		t.Error("Expected an error when moving a missing alias, but got nil")
+	}
+}
+
+func TestMoveAliasOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	notes := filepath.Join(dir, "notes.md")
+	err := os.WriteFile(notes, nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.C{}.AddAlias("notes", notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := filepath.Join(dir, "moved.md")
+	cfg, err = cfg.MoveAliasOnDisk("notes", moved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DirAliases[0].Path != moved || cfg.DirAliases[0].Kind != config.KindFile {
+		t.Errorf("Expected the alias to point to the moved file, but got %+v", cfg.DirAliases[0])
+	}
+	if _, err := os.Stat(moved); err != nil {
+		t.Errorf("Expected the file to be moved, but got %v", err)
+	}
+
+	_, err = cfg.MoveAliasOnDisk("notes", dir)
+	if err == nil {
+		t.Error("Expected an error when moving onto an existing path, but got nil")
+	}
+}
+
+func TestShadowedAliases(t *testing.T) {
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "remove", Path: "/path/to/remove"},
+			{Alias: "proj", Path: "/path/to/proj"},
+			{Alias: "r", Path: "/path/to/r"},
+		},
+	}
+
+	shadowed := cfg.ShadowedAliases()
+	if !reflect.DeepEqual(shadowed, []string{"remove", "r"}) {
+		t.Errorf("Expected [remove r], but got %v", shadowed)
+	}
+
+	_, err := config.C{}.AddAlias("remove", "/path/to/remove")
+	if err == nil {
+		t.Error("Expected an error for a reserved alias, but got nil")
+	}
+}
+
+func TestAddAliasRecordsKind(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.md")
+	err := os.WriteFile(file, []byte("notes"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.C{}.AddAlias("notes", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = cfg.AddAlias("proj", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = cfg.AddAlias("missing", filepath.Join(dir, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, kind := range []string{config.KindFile, config.KindDir, ""} {
+		if cfg.DirAliases[i].Kind != kind {
+			t.Errorf("Expected %v to have kind %q, but got %q", cfg.DirAliases[i].Alias, kind, cfg.DirAliases[i].Kind)
+		}
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.md")
+	err := os.WriteFile(file, []byte("notes"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "remove", Path: dir, Kind: config.KindDir},
+			{Alias: "notes", Path: file, Kind: config.KindFile},
+			{Alias: "changed", Path: dir, Kind: config.KindFile},
+			{Alias: "unknown", Path: file},
+			{Alias: "missing", Path: filepath.Join(dir, "missing"), Kind: config.KindDir},
+		},
+	}
+
+	var aliases []string
+	for _, p := range cfg.Doctor() {
+		aliases = append(aliases, p.Alias)
+	}
+	if !reflect.DeepEqual(aliases, []string{"remove", "changed"}) {
+		t.Errorf("Expected problems with [remove changed], but got %v", aliases)
+	}
+}
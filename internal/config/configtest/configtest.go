@@ -0,0 +1,131 @@
+// Package configtest provides fakes of the interfaces that config.C.Gopen
+// runs on, so that opening aliases can be tested without running editors,
+// touching the filesystem, or cloning repos.
+package configtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// Runner is a config.Runner that records the arguments of the commands
+// instead of running them. Commands whose arguments (joined with spaces) are
+// in Fail return an error, and the ones in Output write it to their stdout.
+// The commands themselves are kept in Cmds, and started commands are
+// recorded separately.
+type Runner struct {
+	Calls   [][]string
+	Cmds    []*exec.Cmd
+	Fail    map[string]bool
+	Output  map[string]string
+	Started []*exec.Cmd
+}
+
+func (r *Runner) Run(cmd *exec.Cmd) error {
+	r.Calls = append(r.Calls, cmd.Args)
+	r.Cmds = append(r.Cmds, cmd)
+
+	key := strings.Join(cmd.Args, " ")
+	if out, ok := r.Output[key]; ok && cmd.Stdout != nil {
+		_, _ = io.WriteString(cmd.Stdout, out)
+	}
+	if r.Fail[key] {
+		return errors.New("exit status 1")
+	}
+
+	return nil
+}
+
+func (r *Runner) Start(cmd *exec.Cmd) error {
+	r.Started = append(r.Started, cmd)
+	return nil
+}
+
+// FS is a config.FS with the directories in Dirs and the files in Files.
+// Every other path doesn't exist.
+type FS struct {
+	Dirs  []string
+	Files []string
+}
+
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	switch {
+	case contains(f.Dirs, name):
+		return fileInfo{name: name, dir: true}, nil
+	case contains(f.Files, name):
+		return fileInfo{name: name}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (f *FS) Glob(pattern string) ([]string, error) {
+	var matches []string
+	for _, p := range append(slices.Clip(f.Dirs), f.Files...) {
+		ok, err := filepath.Match(pattern, filepath.Clean(p))
+		if err != nil {
+			return nil, err
+		}
+		if ok && !slices.Contains(matches, filepath.Clean(p)) {
+			matches = append(matches, filepath.Clean(p))
+		}
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// contains reports whether paths has name, comparing cleaned paths.
+func contains(paths []string, name string) bool {
+	return slices.ContainsFunc(paths, func(p string) bool { return filepath.Clean(p) == filepath.Clean(name) })
+}
+
+type fileInfo struct {
+	name string
+	dir  bool
+}
+
+func (fi fileInfo) Name() string       { return path.Base(filepath.ToSlash(fi.name)) }
+func (fi fileInfo) Size() int64        { return 0 }
+func (fi fileInfo) ModTime() time.Time { return time.Time{} }
+func (fi fileInfo) IsDir() bool        { return fi.dir }
+func (fi fileInfo) Sys() any           { return nil }
+
+func (fi fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+// Cloner is a config.Cloner that records the aliases it clones and adds
+// their paths to FS (if it's set) as directories. If Err is set, cloning
+// fails with it instead.
+type Cloner struct {
+	Cloned []config.DirAlias
+	FS     *FS
+	Err    error
+}
+
+func (c *Cloner) Clone(d config.DirAlias, progress io.Writer) error {
+	if c.Err != nil {
+		return c.Err
+	}
+
+	if progress != nil {
+		fmt.Fprintf(progress, "Cloning into '%v'...\n", d.Path)
+	}
+	c.Cloned = append(c.Cloned, d)
+	if c.FS != nil {
+		c.FS.Dirs = append(c.FS.Dirs, d.Path)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Supported values (or prefixes) of ContainerOptions.Container.
+const (
+	ContainerDevcontainer = "devcontainer"
+	ContainerDocker       = "docker:"
+	ContainerPodman       = "podman:"
+)
+
+// ContainerOptions holds the settings for opening an alias inside a
+// container instead of on the host.
+//
+// Container selects the container and is one of:
+//
+//	devcontainer    start (or attach to) the devcontainer described by the
+//	                project's `.devcontainer` folder using the devcontainer CLI
+//	docker:NAME     start (or attach to) the docker container NAME
+//	podman:NAME     start (or attach to) the podman container NAME
+//
+// Workdir is the directory inside the container to open, and defaults to the
+// workspace folder for devcontainers and to the container's working directory
+// otherwise. If Shell is set, a shell is started in the container instead of
+// the editor.
+//
+// An empty Container opens the alias on the host.
+type ContainerOptions struct {
+	Container string `json:"container,omitempty"`
+	Workdir   string `json:"container_workdir,omitempty"`
+	Shell     bool   `json:"container_shell,omitempty"`
+}
+
+// containerShell starts bash if the container has it and sh otherwise.
+var containerShell = []string{"sh", "-c", "command -v bash >/dev/null && exec bash || exec sh"}
+
+func validateContainer(container string) error {
+	switch {
+	case container == "", container == ContainerDevcontainer:
+		return nil
+	case strings.HasPrefix(container, ContainerDocker) && len(container) > len(ContainerDocker):
+		return nil
+	case strings.HasPrefix(container, ContainerPodman) && len(container) > len(ContainerPodman):
+		return nil
+	}
+
+	return fmt.Errorf("invalid container `%v` (expected devcontainer, docker:NAME, or podman:NAME)", container)
+}
+
+// SetContainerOptions replaces the container options of an existing alias.
+func (cfg C) SetContainerOptions(alias string, opts ContainerOptions) (C, error) {
+	err := validateContainer(opts.Container)
+	if err != nil {
+		return cfg, err
+	}
+
+	if opts.Container == "" && (opts.Workdir != "" || opts.Shell) {
+		return cfg, errors.New("container options need a container")
+	}
+
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		if d.IsRemote() && opts.Container != "" {
+			return errors.New("containers aren't supported for remote aliases")
+		}
+		d.ContainerOptions = opts
+		return nil
+	})
+}
+
+// ContainerCommands returns the commands that open the alias in its
+// container, in the order they should be run: the first starts the container
+// and the second launches the editor (or a shell) in it. The path is passed
+// to the editor as `.` since it's resolved inside the container, and as with
+// the host, customBehaviour leaves it out. editor is the editor command and
+// its arguments, and env holds KEY=VALUE pairs set inside the container.
+func (d DirAlias) ContainerCommands(editor, env []string, customBehaviour bool) ([]*exec.Cmd, error) {
+	err := validateContainer(d.Container)
+	if err != nil {
+		return nil, err
+	}
+	if d.Container == "" {
+		return nil, fmt.Errorf("alias `%v` has no container", d.Alias)
+	}
+
+	inner := containerShell
+	if !d.Shell {
+		if len(editor) == 0 {
+			return nil, errors.New("no editor command is set")
+		}
+		inner = slices.Clip(editor)
+		if !customBehaviour {
+			inner = append(inner, ".")
+		}
+	}
+
+	if d.Container == ContainerDevcontainer {
+		up := exec.Command("devcontainer", "up", "--workspace-folder", d.Path)
+
+		execArgs := []string{"exec", "--workspace-folder", d.Path}
+		for _, kv := range env {
+			execArgs = append(execArgs, "--remote-env", kv)
+		}
+		if d.Workdir != "" {
+			// devcontainer exec has no workdir flag, so the command is
+			// wrapped in a shell that changes into it first
+			script := `cd "$1" && shift && exec "$@"`
+			inner = append([]string{"sh", "-c", script, "sh", d.Workdir}, inner...)
+		}
+		open := exec.Command("devcontainer", append(execArgs, inner...)...)
+		open.Dir = d.Path
+
+		return []*exec.Cmd{up, open}, nil
+	}
+
+	runtime, name, _ := strings.Cut(d.Container, ":")
+	start := exec.Command(runtime, "start", name)
+
+	execArgs := []string{"exec", "-it"}
+	for _, kv := range env {
+		execArgs = append(execArgs, "-e", kv)
+	}
+	if d.Workdir != "" {
+		execArgs = append(execArgs, "-w", d.Workdir)
+	}
+	execArgs = append(execArgs, name)
+	open := exec.Command(runtime, append(execArgs, inner...)...)
+
+	return []*exec.Cmd{start, open}, nil
+}
@@ -0,0 +1,150 @@
+package config_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/config/configtest"
+)
+
+func TestContainerCommands(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     config.ContainerOptions
+		env      []string
+		expected [][]string
+	}{
+		{
+			name: "devcontainer",
+			opts: config.ContainerOptions{Container: "devcontainer"},
+			expected: [][]string{
+				{"devcontainer", "up", "--workspace-folder", "/code/proj"},
+				{"devcontainer", "exec", "--workspace-folder", "/code/proj", "nvim", "."},
+			},
+		},
+		{
+			name: "devcontainer with workdir",
+			opts: config.ContainerOptions{Container: "devcontainer", Workdir: "/src"},
+			expected: [][]string{
+				{"devcontainer", "up", "--workspace-folder", "/code/proj"},
+				{"devcontainer", "exec", "--workspace-folder", "/code/proj",
+					"sh", "-c", `cd "$1" && shift && exec "$@"`, "sh", "/src", "nvim", "."},
+			},
+		},
+		{
+			name: "devcontainer with env",
+			opts: config.ContainerOptions{Container: "devcontainer"},
+			env:  []string{"A=1", "B=2"},
+			expected: [][]string{
+				{"devcontainer", "up", "--workspace-folder", "/code/proj"},
+				{"devcontainer", "exec", "--workspace-folder", "/code/proj",
+					"--remote-env", "A=1", "--remote-env", "B=2", "nvim", "."},
+			},
+		},
+		{
+			name: "docker with env",
+			opts: config.ContainerOptions{Container: "docker:dev"},
+			env:  []string{"A=1"},
+			expected: [][]string{
+				{"docker", "start", "dev"},
+				{"docker", "exec", "-it", "-e", "A=1", "dev", "nvim", "."},
+			},
+		},
+		{
+			name: "docker with workdir",
+			opts: config.ContainerOptions{Container: "docker:dev", Workdir: "/app"},
+			expected: [][]string{
+				{"docker", "start", "dev"},
+				{"docker", "exec", "-it", "-w", "/app", "dev", "nvim", "."},
+			},
+		},
+		{
+			name: "podman shell",
+			opts: config.ContainerOptions{Container: "podman:dev", Shell: true},
+			expected: [][]string{
+				{"podman", "start", "dev"},
+				{"podman", "exec", "-it", "dev", "sh", "-c", "command -v bash >/dev/null && exec bash || exec sh"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		d := config.DirAlias{Alias: "proj", Path: "/code/proj", ContainerOptions: c.opts}
+		cmds, err := d.ContainerCommands([]string{"nvim"}, c.env, false)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.name, err)
+			continue
+		}
+
+		var actual [][]string
+		for _, cmd := range cmds {
+			actual = append(actual, cmd.Args)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%v: Expected %q, but got %q", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestGopenContainer(t *testing.T) {
+	cfg := config.C{
+		EditorCmd:       "code --wait",
+		CustomBehaviour: true,
+		DirAliases: []config.DirAlias{
+			{
+				Alias:            "proj",
+				Path:             t.TempDir(),
+				ContainerOptions: config.ContainerOptions{Container: "docker:dev"},
+			},
+		},
+	}
+
+	r := &configtest.Runner{}
+	err := cfg.GopenWith("proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"docker", "start", "dev"},
+		{"docker", "exec", "-it", "dev", "code", "--wait"},
+	}
+	if !reflect.DeepEqual(r.Calls, expected) {
+		t.Errorf("Expected %q, but got %q", expected, r.Calls)
+	}
+}
+
+func TestSetContainerOptions(t *testing.T) {
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "proj", Path: "/code/proj"},
+			{Alias: "vm", Path: "/code/vm", Host: "devvm"},
+		},
+	}
+
+	opts := config.ContainerOptions{Container: "docker:dev", Workdir: "/app"}
+	newCfg, err := cfg.SetContainerOptions("proj", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newCfg.DirAliases[0].ContainerOptions != opts {
+		t.Errorf("Expected %v, but got %v", opts, newCfg.DirAliases[0].ContainerOptions)
+	}
+
+	invalid := []struct {
+		alias string
+		opts  config.ContainerOptions
+	}{
+		{"proj", config.ContainerOptions{Container: "docker:"}},
+		{"proj", config.ContainerOptions{Container: "lxc:dev"}},
+		{"proj", config.ContainerOptions{Shell: true}},
+		{"vm", config.ContainerOptions{Container: "devcontainer"}},
+	}
+	for _, c := range invalid {
+		_, err = cfg.SetContainerOptions(c.alias, c.opts)
+		if err == nil {
+			t.Errorf("Expected an error for %v with %+v, but got nil", c.alias, c.opts)
+		}
+	}
+}
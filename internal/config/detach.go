@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Supported values of C.Detach and DirAlias.Detach.
+const (
+	DetachAuto   = "auto"
+	DetachAlways = "always"
+	DetachNever  = "never"
+)
+
+// GUIEditors are the programs that are detached in `auto` mode. Names are
+// compared without extensions, so `code` also matches `code.cmd`.
+var GUIEditors = []string{
+	"atom", "clion", "code", "code-insiders", "codium", "cursor", "fleet",
+	"gedit", "goland", "idea", "idea64", "kate", "mousepad", "phpstorm",
+	"pycharm", "rider", "rubymine", "studio", "subl", "sublime_text",
+	"webstorm", "windsurf", "zed", "zeditor",
+}
+
+func validateDetach(detach string) error {
+	if !slices.Contains([]string{"", DetachAuto, DetachAlways, DetachNever}, detach) {
+		return fmt.Errorf("invalid detach mode `%v` (expected auto, always, or never)", detach)
+	}
+	return nil
+}
+
+// SetDetach sets the detach mode of the editor. An empty mode is the same as
+// `auto`.
+func (cfg C) SetDetach(detach string) (C, error) {
+	err := validateDetach(detach)
+	if err != nil {
+		return cfg, err
+	}
+
+	newCfg := cfg
+	newCfg.Detach = detach
+	return newCfg, nil
+}
+
+// SetAliasDetach overrides the detach mode of the editor for an existing
+// alias. An empty mode uses the editor's mode again.
+func (cfg C) SetAliasDetach(alias string, detach string) (C, error) {
+	err := validateDetach(detach)
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		d.Detach = detach
+		return nil
+	})
+}
+
+// shouldDetach reports whether cmd, which opens target with editor, is
+// detached. The mode of the alias takes precedence over the mode of the
+// editor, and in `auto` mode only GUI editors are detached.
+func shouldDetach(target DirAlias, editor EditorProfile, cmd *exec.Cmd) bool {
+	mode := target.Detach
+	if mode == "" {
+		mode = editor.Detach
+	}
+
+	switch mode {
+	case DetachAlways:
+		return true
+	case DetachNever:
+		return false
+	}
+
+	return IsGUIEditor(cmd.Args[0])
+}
+
+// IsGUIEditor reports whether program is one of GUIEditors.
+func IsGUIEditor(program string) bool {
+	name := filepath.Base(program)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return slices.Contains(GUIEditors, name)
+}
+
+// detach starts cmd in its own session without waiting for it, sending its
+// output to the log file of alias.
+func detach(alias string, cmd *exec.Cmd, runner Runner) error {
+	path, err := LogPath(alias)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+
+	fmt.Fprintf(log, "== %v: %v\n", time.Now().Format(time.RFC3339), strings.Join(cmd.Args, " "))
+
+	cmd.Stdin = nil
+	cmd.Stdout = log
+	cmd.Stderr = log
+	setDetached(cmd)
+
+	return runner.Start(cmd)
+}
+
+// LogPath returns the path of the file that the output of detached editors
+// opened for alias is appended to.
+func LogPath(alias string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "gopen", "logs", SessionName(alias)+".log"), nil
+}
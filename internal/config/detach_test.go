@@ -0,0 +1,103 @@
+package config_test
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/config/configtest"
+)
+
+func TestGopenDetach(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	cases := []struct {
+		name        string
+		editorCmd   string
+		detach      string
+		aliasDetach string
+		detached    bool
+	}{
+		{"GUI editor", "code", "", "", true},
+		{"terminal editor", "nvim", "", "", false},
+		{"always", "nvim", config.DetachAlways, "", true},
+		{"never", "/usr/local/bin/zed", config.DetachNever, "", false},
+		{"alias overrides config", "code", config.DetachAlways, config.DetachNever, false},
+	}
+
+	for _, c := range cases {
+		cfg := config.C{
+			EditorCmd: c.editorCmd,
+			Detach:    c.detach,
+			DirAliases: []config.DirAlias{
+				{Alias: "proj", Path: dir, Detach: c.aliasDetach},
+			},
+		}
+
+		r := &configtest.Runner{}
+		err := cfg.GopenWith("proj", config.OpenOptions{Runner: r})
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.name, err)
+			continue
+		}
+
+		if detached := len(r.Started) == 1; detached != c.detached || len(r.Calls)+len(r.Started) != 1 {
+			t.Errorf("%v: Expected detached to be %v, but got %v run and %v started", c.name, c.detached, r.Calls, len(r.Started))
+		}
+	}
+}
+
+func TestDetachLogsOutput(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	cfg := config.C{EditorCmd: "code", DirAliases: []config.DirAlias{{Alias: "proj", Path: dir}}}
+
+	r := &configtest.Runner{}
+	err := cfg.GopenWith("proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := r.Started[0]
+	if !reflect.DeepEqual(cmd.Args, []string{"code", dir}) {
+		t.Errorf("Expected to start [code %v], but got %q", dir, cmd.Args)
+	}
+	if cmd.SysProcAttr == nil || cmd.Stdin != nil {
+		t.Error("Expected the editor to be detached from the terminal")
+	}
+
+	logPath, err := config.LogPath("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, ok := cmd.Stdout.(*os.File); !ok || f.Name() != logPath {
+		t.Errorf("Expected the output to go to %v, but got %v", logPath, cmd.Stdout)
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "code "+dir) {
+		t.Errorf("Expected the log to mention the command, but got %q", log)
+	}
+}
+
+func TestIsGUIEditor(t *testing.T) {
+	cases := map[string]bool{
+		"code":         true,
+		"/usr/bin/zed": true,
+		"code.cmd":     true,
+		"nvim":         false,
+		"ssh":          false,
+	}
+
+	for program, expected := range cases {
+		if actual := config.IsGUIEditor(program); actual != expected {
+			t.Errorf("%v: Expected %v, but got %v", program, expected, actual)
+		}
+	}
+}
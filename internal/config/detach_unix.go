@@ -0,0 +1,14 @@
+//go:build !windows
+
+package config
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setDetached makes cmd start in a new session so that it isn't tied to the
+// terminal that Gopen runs in.
+func setDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
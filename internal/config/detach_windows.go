@@ -0,0 +1,20 @@
+//go:build windows
+
+package config
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess is the DETACHED_PROCESS process creation flag, which isn't
+// defined in syscall.
+const detachedProcess = 0x00000008
+
+// setDetached makes cmd start without a console and in a new process group so
+// that it isn't tied to the terminal that Gopen runs in.
+func setDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess,
+	}
+}
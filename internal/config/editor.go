@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// EditorProfile is a named editor configuration. Args are passed to Command
+// before the project path, Detach overrides the detach mode of the config
+// unless it's empty, and Env is added to the environment of the editor.
+type EditorProfile struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Detach  string            `json:"detach,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// KnownEditors are the editors that DetectEditors looks for, in order of
+// preference.
+var KnownEditors = []string{"nvim", "vim", "code", "zed", "hx", "emacs", "subl"}
+
+// DetectEditors returns the commands of KnownEditors that are found on PATH.
+func DetectEditors() []string {
+	var found []string
+	for _, name := range KnownEditors {
+		if _, err := exec.LookPath(name); err == nil {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+// EnvEditor returns the editor command set in $VISUAL or, if that's empty,
+// $EDITOR.
+func EnvEditor() string {
+	if visual := os.Getenv("VISUAL"); visual != "" {
+		return visual
+	}
+	return os.Getenv("EDITOR")
+}
+
+// AddEditor adds an editor profile, replacing the profile with the same name
+// if there's one.
+func (cfg C) AddEditor(profile EditorProfile) (C, error) {
+	if profile.Name == "" || strings.ContainsAny(profile.Name, " \t") {
+		return cfg, fmt.Errorf("invalid editor profile name `%v`", profile.Name)
+	}
+	if profile.Command == "" {
+		return cfg, errors.New("editor profiles need a command")
+	}
+
+	err := validateDetach(profile.Detach)
+	if err != nil {
+		return cfg, err
+	}
+
+	newCfg := cfg
+	i := slices.IndexFunc(cfg.Editors, func(p EditorProfile) bool { return p.Name == profile.Name })
+	if i == -1 {
+		newCfg.Editors = append(slices.Clip(cfg.Editors), profile)
+	} else {
+		newCfg.Editors = slices.Clone(cfg.Editors)
+		newCfg.Editors[i] = profile
+	}
+
+	return newCfg, nil
+}
+
+// RemoveEditor removes an editor profile. If it was the default, EditorCmd is
+// used again.
+func (cfg C) RemoveEditor(name string) (C, error) {
+	i := slices.IndexFunc(cfg.Editors, func(p EditorProfile) bool { return p.Name == name })
+	if i == -1 {
+		return cfg, fmt.Errorf("editor profile `%v` doesn't exist", name)
+	}
+
+	newCfg := cfg
+	newCfg.Editors = slices.Delete(slices.Clone(cfg.Editors), i, i+1)
+	if cfg.DefaultEditor == name {
+		newCfg.DefaultEditor = ""
+	}
+
+	return newCfg, nil
+}
+
+// UseEditor makes the profile name the default editor. An empty name uses
+// EditorCmd again.
+func (cfg C) UseEditor(name string) (C, error) {
+	if name != "" && !slices.ContainsFunc(cfg.Editors, func(p EditorProfile) bool { return p.Name == name }) {
+		return cfg, fmt.Errorf("editor profile `%v` doesn't exist", name)
+	}
+
+	newCfg := cfg
+	newCfg.DefaultEditor = name
+	return newCfg, nil
+}
+
+// Editor returns the editor profile called name. An empty name returns the
+// default profile, or a profile made from EditorCmd if there's none. A name
+// that doesn't match a profile is used as an editor command, for one-off
+// overrides like `gopen foo -e code`.
+func (cfg C) Editor(name string) (EditorProfile, error) {
+	override := name != ""
+	if name == "" {
+		name = cfg.DefaultEditor
+	}
+
+	for _, p := range cfg.Editors {
+		if p.Name == name {
+			if p.Detach == "" {
+				p.Detach = cfg.Detach
+			}
+			return p, nil
+		}
+	}
+
+	if name != "" && !override {
+		return EditorProfile{}, fmt.Errorf("default editor profile `%v` doesn't exist", name)
+	}
+
+	cmdLine := cfg.EditorCmd
+	if override {
+		cmdLine = name
+	}
+
+	p, ok := cfg.commandProfile(cmdLine)
+	if !ok {
+		return EditorProfile{}, errors.New("Editor command not set\nSet it with `gopen editor youreditor`")
+	}
+	return p, nil
+}
+
+// Sources of the editor returned by ResolveEditor, from the highest
+// precedence to the lowest.
+const (
+	EditorFromOverride = "override"
+	EditorFromAlias    = "alias"
+	EditorFromRule     = "rule"
+	EditorFromConfig   = "config"
+	EditorFromVisual   = "$VISUAL"
+	EditorFromEditor   = "$EDITOR"
+	EditorFromPlatform = "platform default"
+)
+
+// ResolveEditor returns the editor that opens alias, along with the source it
+// came from. The first of these that is set wins: override (e.g., the `-e`
+// flag), the editor of the alias, the first of EditorRules that matches the
+// alias, the config's default profile or EditorCmd, $VISUAL, $EDITOR, and
+// finally the platform's default editor. An empty alias skips the alias'
+// editor and the rules.
+func (cfg C) ResolveEditor(alias string, override string) (EditorProfile, string, error) {
+	return cfg.ResolveEditorWith(alias, OpenOptions{Editor: override})
+}
+
+// ResolveEditorWith works like ResolveEditor with opts.Editor as the
+// override, matching the rules against opts.FS.
+func (cfg C) ResolveEditorWith(alias string, opts OpenOptions) (EditorProfile, string, error) {
+	opts = opts.withDefaults()
+	override := opts.Editor
+	if override != "" {
+		p, err := cfg.Editor(override)
+		return p, EditorFromOverride, err
+	}
+
+	if alias != "" {
+		selected, err := cfg.Select([]string{alias}, "")
+		if err != nil {
+			return EditorProfile{}, "", err
+		}
+		if selected[0].Editor != "" {
+			p, err := cfg.Editor(selected[0].Editor)
+			return p, EditorFromAlias, err
+		}
+		if rule, _, ok := cfg.MatchEditorRule(selected[0], opts.FS); ok {
+			p, err := cfg.Editor(rule.Editor)
+			return p, EditorFromRule, err
+		}
+	}
+
+	if cfg.DefaultEditor != "" || cfg.EditorCmd != "" {
+		p, err := cfg.Editor("")
+		return p, EditorFromConfig, err
+	}
+
+	if p, ok := cfg.commandProfile(os.Getenv("VISUAL")); ok {
+		return p, EditorFromVisual, nil
+	}
+	if p, ok := cfg.commandProfile(os.Getenv("EDITOR")); ok {
+		return p, EditorFromEditor, nil
+	}
+
+	p, _ := cfg.commandProfile(platformEditor())
+	return p, EditorFromPlatform, nil
+}
+
+// SetAliasEditor makes an existing alias open with editor, which is an editor
+// profile or command. An empty editor uses the default one again.
+func (cfg C) SetAliasEditor(alias string, editor string) (C, error) {
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		d.Editor = editor
+		return nil
+	})
+}
+
+// commandProfile returns an unnamed profile that runs cmdLine, split on
+// spaces, or false if cmdLine is empty.
+func (cfg C) commandProfile(cmdLine string) (EditorProfile, bool) {
+	fields := strings.Fields(cmdLine)
+	if len(fields) == 0 {
+		return EditorProfile{}, false
+	}
+	return EditorProfile{Command: fields[0], Args: fields[1:], Detach: cfg.Detach}, true
+}
+
+// platformEditor returns the editor that is always there on this platform.
+func platformEditor() string {
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// EditorNames returns the names of the editor profiles in order.
+func (cfg C) EditorNames() []string {
+	var names []string
+	for _, p := range cfg.Editors {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Argv returns the command and arguments of the profile.
+func (p EditorProfile) Argv() []string {
+	return append([]string{p.Command}, p.Args...)
+}
+
+// CommandLine returns the command and arguments of the profile as a single
+// line for a POSIX shell.
+func (p EditorProfile) CommandLine() string {
+	return shellJoin(p.Argv())
+}
+
+// command returns the command that runs the editor with extra arguments
+// after the profile's own.
+func (p EditorProfile) command(extra ...string) *exec.Cmd {
+	args := append(slices.Clip(p.Args), extra...)
+	cmd := exec.Command(p.Command, args...)
+	p.setEnv(cmd)
+	return cmd
+}
+
+// setEnv adds the environment of the profile to cmd.
+func (p EditorProfile) setEnv(cmd *exec.Cmd) {
+	env := p.envList()
+	if len(env) == 0 {
+		return
+	}
+
+	cmd.Env = append(os.Environ(), env...)
+}
+
+// envList returns the environment of the profile as KEY=VALUE pairs sorted
+// by key.
+func (p EditorProfile) envList() []string {
+	keys := make([]string, 0, len(p.Env))
+	for k := range p.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var env []string
+	for _, k := range keys {
+		env = append(env, k+"="+p.Env[k])
+	}
+	return env
+}
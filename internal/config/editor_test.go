@@ -0,0 +1,176 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/config/configtest"
+)
+
+func TestEditorProfiles(t *testing.T) {
+	cfg := config.C{EditorCmd: "nvim -p", Detach: config.DetachNever}
+
+	editor, err := cfg.Editor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := config.EditorProfile{Command: "nvim", Args: []string{"-p"}, Detach: config.DetachNever}
+	if !reflect.DeepEqual(editor, expected) {
+		t.Errorf("Expected EditorCmd as the default editor %+v, but got %+v", expected, editor)
+	}
+
+	goland := config.EditorProfile{Name: "goland", Command: "goland", Detach: config.DetachAlways}
+	cfg, err = cfg.AddEditor(goland)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = cfg.AddEditor(config.EditorProfile{Name: "code", Command: "code", Args: []string{"-n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = cfg.UseEditor("goland")
+	if err != nil {
+		t.Fatal(err)
+	}
+	editor, err = cfg.Editor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(editor, goland) {
+		t.Errorf("Expected the default profile %+v, but got %+v", goland, editor)
+	}
+
+	editor, err = cfg.Editor("code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if editor.Detach != config.DetachNever {
+		t.Errorf("Expected a profile without a detach mode to use the config's, but got %q", editor.Detach)
+	}
+
+	editor, err = cfg.Editor("hx --vsplit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(editor.Argv(), []string{"hx", "--vsplit"}) {
+		t.Errorf("Expected an unknown name to be used as a command, but got %q", editor.Argv())
+	}
+
+	cfg, err = cfg.RemoveEditor("goland")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultEditor != "" || !slices.Equal(cfg.EditorNames(), []string{"code"}) {
+		t.Errorf("Expected only `code` to be left and no default, but got %v and %q", cfg.EditorNames(), cfg.DefaultEditor)
+	}
+
+	_, err = cfg.UseEditor("goland")
+	if err == nil {
+		t.Error("Expected an error for a missing profile, but got nil")
+	}
+
+	_, err = cfg.AddEditor(config.EditorProfile{Name: "empty"})
+	if err == nil {
+		t.Error("Expected an error for a profile without a command, but got nil")
+	}
+}
+
+func TestGopenWithEditor(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.C{
+		EditorCmd: "nvim",
+		Editors: []config.EditorProfile{
+			{Name: "hx", Command: "hx", Args: []string{"-c", "hx.toml"}, Env: map[string]string{"HELIX_RUNTIME": "/rt"}},
+		},
+		DirAliases: []config.DirAlias{{Alias: "proj", Path: dir}},
+	}
+
+	r := &configtest.Runner{}
+	err := cfg.GopenWith("proj", config.OpenOptions{Editor: "hx", Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"hx", "-c", "hx.toml", dir}}
+	if !reflect.DeepEqual(r.Calls, expected) {
+		t.Errorf("Expected %q, but got %q", expected, r.Calls)
+	}
+	if !slices.Contains(r.Cmds[0].Env, "HELIX_RUNTIME=/rt") {
+		t.Error("Expected the profile's env to be set")
+	}
+}
+
+func TestDetectEditors(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"hx", "code", "notepad"} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+
+	expected := []string{"code", "hx"}
+	actual := config.DetectEditors()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, but got %v", expected, actual)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "vi")
+	if config.EnvEditor() != "vi" {
+		t.Errorf("Expected $EDITOR to be used, but got %q", config.EnvEditor())
+	}
+
+	t.Setenv("VISUAL", "code -w")
+	if config.EnvEditor() != "code -w" {
+		t.Errorf("Expected $VISUAL to take precedence, but got %q", config.EnvEditor())
+	}
+}
+
+func TestResolveEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	cfg := config.C{DirAliases: []config.DirAlias{{Alias: "proj", Path: "/proj"}, {Alias: "web", Path: "/web", Editor: "code"}}}
+
+	cases := []struct {
+		setup    func()
+		alias    string
+		override string
+		expected string
+		source   string
+	}{
+		{func() {}, "proj", "", "vi", config.EditorFromPlatform},
+		{func() { t.Setenv("EDITOR", "nano") }, "proj", "", "nano", config.EditorFromEditor},
+		{func() { t.Setenv("VISUAL", "gedit") }, "proj", "", "gedit", config.EditorFromVisual},
+		{func() { cfg.EditorCmd = "nvim" }, "proj", "", "nvim", config.EditorFromConfig},
+		{func() {}, "web", "", "code", config.EditorFromAlias},
+		{func() {}, "web", "hx", "hx", config.EditorFromOverride},
+	}
+
+	for _, c := range cases {
+		if runtime.GOOS == "windows" && c.source == config.EditorFromPlatform {
+			continue
+		}
+
+		c.setup()
+		editor, source, err := cfg.ResolveEditor(c.alias, c.override)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if editor.Command != c.expected || source != c.source {
+			t.Errorf("Expected %v from %v, but got %v from %v", c.expected, c.source, editor.Command, source)
+		}
+	}
+
+	_, _, err := cfg.ResolveEditor("nope", "")
+	if err == nil {
+		t.Error("Expected an error for a non-existent alias, but got nil")
+	}
+}
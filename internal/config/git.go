@@ -0,0 +1,225 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Supported values (or prefixes) of GitOptions.Auth.
+const (
+	AuthSSHAgent         = "ssh-agent"
+	AuthSSHKey           = "ssh-key:"
+	AuthTokenEnv         = "token-env:"
+	AuthCredentialHelper = "credential-helper"
+)
+
+// GitOptions holds the settings used when cloning the git repo of an alias.
+//
+// Auth selects how to authenticate against the remote and is one of:
+//
+//	ssh-agent              use keys loaded in the running ssh-agent
+//	ssh-key:path/to/key    use a private key file; a passphrase is read
+//	                       from $GOPEN_SSH_PASSPHRASE if the key needs one
+//	token-env:VAR          use the token stored in environment variable VAR
+//	                       over HTTPS
+//	credential-helper      ask `git credential fill` for HTTPS credentials
+//
+// An empty Auth leaves the choice to go-git's defaults.
+type GitOptions struct {
+	Branch     string `json:"git_branch,omitempty"`
+	Depth      int    `json:"git_depth,omitempty"`
+	Submodules bool   `json:"git_submodules,omitempty"`
+	Auth       string `json:"git_auth,omitempty"`
+}
+
+func validateGitAuth(auth string) error {
+	switch {
+	case auth == "", auth == AuthSSHAgent, auth == AuthCredentialHelper:
+		return nil
+	case strings.HasPrefix(auth, AuthSSHKey) && len(auth) > len(AuthSSHKey):
+		return nil
+	case strings.HasPrefix(auth, AuthTokenEnv) && len(auth) > len(AuthTokenEnv):
+		return nil
+	}
+
+	return fmt.Errorf("invalid git auth method `%v`", auth)
+}
+
+// AuthMethod returns the go-git auth method described by the Auth option of
+// d, or nil if none is set. It isn't a method so that go-git types stay out
+// of the method set of DirAlias, which pkg/gopen exposes.
+func AuthMethod(d DirAlias) (transport.AuthMethod, error) {
+	err := validateGitAuth(d.Auth)
+	if err != nil || d.Auth == "" {
+		return nil, err
+	}
+
+	ep, err := transport.NewEndpoint(d.GitRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ep.User
+	if user == "" {
+		user = gitssh.DefaultUsername
+	}
+
+	switch {
+	case d.Auth == AuthSSHAgent:
+		return gitssh.NewSSHAgentAuth(user)
+
+	case strings.HasPrefix(d.Auth, AuthSSHKey):
+		keyPath, err := ExpandHome(strings.TrimPrefix(d.Auth, AuthSSHKey))
+		if err != nil {
+			return nil, err
+		}
+		return gitssh.NewPublicKeysFromFile(user, keyPath, os.Getenv("GOPEN_SSH_PASSPHRASE"))
+
+	case strings.HasPrefix(d.Auth, AuthTokenEnv):
+		envVar := strings.TrimPrefix(d.Auth, AuthTokenEnv)
+		token := os.Getenv(envVar)
+		if token == "" {
+			return nil, fmt.Errorf("environment variable %v is empty", envVar)
+		}
+		return &http.BasicAuth{Username: user, Password: token}, nil
+
+	default:
+		return credentialHelperAuth(ep)
+	}
+}
+
+// credentialHelperAuth asks the git credential helpers configured on the
+// system for the credentials of ep.
+func credentialHelperAuth(ep *transport.Endpoint) (transport.AuthMethod, error) {
+	host := ep.Host
+	if ep.Port != 0 {
+		host = fmt.Sprintf("%v:%v", ep.Host, ep.Port)
+	}
+
+	input := fmt.Sprintf("protocol=%v\nhost=%v\npath=%v\n", ep.Protocol, host, strings.TrimPrefix(ep.Path, "/"))
+	if ep.User != "" {
+		input += fmt.Sprintf("username=%v\n", ep.User)
+	}
+
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input + "\n")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git credential helper failed: %v", err)
+	}
+
+	auth, err := parseCredentials(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+func parseCredentials(out []byte) (*http.BasicAuth, error) {
+	auth := &http.BasicAuth{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "username":
+			auth.Username = value
+		case "password":
+			auth.Password = value
+		}
+	}
+
+	if auth.Password == "" {
+		return nil, fmt.Errorf("git credential helper returned no password")
+	}
+
+	return auth, scanner.Err()
+}
+
+// CloneOptions builds the go-git clone options for the repo of d. Clone
+// progress is written to progress, which may be nil. Like AuthMethod, it
+// isn't a method of DirAlias.
+func CloneOptions(d DirAlias, progress io.Writer) (*git.CloneOptions, error) {
+	auth, err := AuthMethod(d)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &git.CloneOptions{
+		URL:      d.GitRepo,
+		Auth:     auth,
+		Depth:    d.Depth,
+		Progress: progress,
+	}
+
+	if d.Branch != "" {
+		opts.ReferenceName = plumbing.NewBranchReferenceName(d.Branch)
+	}
+
+	if d.Submodules {
+		opts.RecurseSubmodules = git.DefaultSubmoduleRecursionDepth
+	}
+
+	return opts, nil
+}
+
+// Clone clones the alias' git repo into its path. Remote aliases are cloned
+// on their host.
+func (d DirAlias) Clone(progress io.Writer) error {
+	if d.IsRemote() {
+		return d.cloneRemote(DefaultRunner, progress)
+	}
+
+	opts, err := CloneOptions(d, progress)
+	if err != nil {
+		return err
+	}
+
+	_, err = git.PlainClone(d.Path, false, opts)
+	return err
+}
+
+// DetectRemote returns the first URL of the `origin` remote of the git repo at
+// path. An empty string is returned if path isn't a git repo or has no
+// `origin` remote.
+func DetectRemote(path string) (string, error) {
+	r, err := git.PlainOpen(path)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	remote, err := r.Remote(git.DefaultRemoteName)
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", nil
+	}
+
+	return urls[0], nil
+}
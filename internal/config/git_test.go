@@ -0,0 +1,277 @@
+package config_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// newBareRepo creates a bare repo with two commits on `master` and one extra
+// commit on branch `dev`, and returns its path.
+func newBareRepo(t *testing.T) string {
+	t.Helper()
+
+	// Cloning over the file transport shells out to git-upload-pack
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	srcDir := t.TempDir()
+	repo, err := git.PlainInit(srcDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := func(file string) {
+		err := os.WriteFile(filepath.Join(srcDir, file), []byte(file), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = w.Add(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = w.Commit(file, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit("one.txt")
+	commit("two.txt")
+
+	err = w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("dev"), Create: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit("dev.txt")
+
+	bareDir := filepath.Join(t.TempDir(), "bare.git")
+	_, err = git.PlainClone(bareDir, true, &git.CloneOptions{URL: srcDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bareDir
+}
+
+func TestCloneDefaultBranch(t *testing.T) {
+	bare := newBareRepo(t)
+	dirAlias := config.DirAlias{
+		Alias:   "proj",
+		Path:    filepath.Join(t.TempDir(), "proj"),
+		GitRepo: bare,
+	}
+
+	err := dirAlias.Clone(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(filepath.Join(dirAlias.Path, "two.txt"))
+	if err != nil {
+		t.Errorf("Expected two.txt to be cloned: %v", err)
+	}
+}
+
+func TestCloneBranchAndDepth(t *testing.T) {
+	bare := newBareRepo(t)
+	dirAlias := config.DirAlias{
+		Alias:   "proj",
+		Path:    filepath.Join(t.TempDir(), "proj"),
+		GitRepo: "file://" + bare,
+		GitOptions: config.GitOptions{
+			Branch: "dev",
+			Depth:  1,
+		},
+	}
+
+	err := dirAlias.Clone(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(filepath.Join(dirAlias.Path, "dev.txt"))
+	if err != nil {
+		t.Errorf("Expected dev.txt to be cloned: %v", err)
+	}
+
+	repo, err := git.PlainOpen(dirAlias.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Name().Short() != "dev" {
+		t.Errorf("Expected branch dev, but got %v", head.Name().Short())
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	_ = commits.ForEach(func(*object.Commit) error {
+		count++
+		return nil
+	})
+	if count != 1 {
+		t.Errorf("Expected a shallow clone with 1 commit, but got %v", count)
+	}
+}
+
+func TestCloneOptionsSubmodules(t *testing.T) {
+	dirAlias := config.DirAlias{GitRepo: "https://example.com/repo.git"}
+	dirAlias.Submodules = true
+
+	opts, err := config.CloneOptions(dirAlias, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.RecurseSubmodules != git.DefaultSubmoduleRecursionDepth {
+		t.Errorf("Expected submodule recursion, but got %v", opts.RecurseSubmodules)
+	}
+}
+
+func TestAuthMethodTokenEnv(t *testing.T) {
+	t.Setenv("GOPEN_TEST_TOKEN", "s3cret")
+	dirAlias := config.DirAlias{
+		GitRepo:    "https://example.com/repo.git",
+		GitOptions: config.GitOptions{Auth: "token-env:GOPEN_TEST_TOKEN"},
+	}
+
+	auth, err := config.AuthMethod(dirAlias)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basic, ok := auth.(*http.BasicAuth)
+	if !ok {
+		t.Fatalf("Expected *http.BasicAuth, but got %T", auth)
+	}
+	if basic.Password != "s3cret" {
+		t.Errorf("Expected the token as password, but got %q", basic.Password)
+	}
+
+	t.Setenv("GOPEN_TEST_TOKEN", "")
+	_, err = config.AuthMethod(dirAlias)
+	if err == nil {
+		t.Error("Expected an error for an empty token, but got nil")
+	}
+}
+
+func TestSetGitOptions(t *testing.T) {
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "proj", Path: "/path/to/proj", GitRepo: "git@example.com:proj.git"},
+		},
+	}
+
+	opts := config.GitOptions{Branch: "main", Depth: 1, Submodules: true, Auth: "ssh-agent"}
+	newCfg, err := cfg.SetGitOptions("proj", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newCfg.DirAliases[0].GitOptions != opts {
+		t.Errorf("Expected %v, but got %v", opts, newCfg.DirAliases[0].GitOptions)
+	}
+
+	newCfg, err = newCfg.SetGitRepo("proj", "git@example.com:other.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newCfg.DirAliases[0].GitOptions != opts {
+		t.Errorf("Expected SetGitRepo to keep %v, but got %v", opts, newCfg.DirAliases[0].GitOptions)
+	}
+
+	_, err = cfg.SetGitOptions("proj", config.GitOptions{Auth: "password"})
+	if err == nil {
+		t.Error("Expected an error for an invalid auth method, but got nil")
+	}
+
+	_, err = cfg.SetGitOptions("nope", opts)
+	if err == nil {
+		t.Error("Expected an error for a missing alias, but got nil")
+	}
+}
+
+func newRepoWithOrigin(t *testing.T, url string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{url}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestAddAliasDetectsRemote(t *testing.T) {
+	url := "git@example.com:me/proj.git"
+	dir := newRepoWithOrigin(t, url)
+
+	cfg, err := config.C{}.AddAlias("proj", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DirAliases[0].GitRepo != url {
+		t.Errorf("Expected git repo %q, but got %q", url, cfg.DirAliases[0].GitRepo)
+	}
+
+	cfg, err = config.C{}.AddAlias("plain", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DirAliases[0].GitRepo != "" {
+		t.Errorf("Expected no git repo for a plain directory, but got %q", cfg.DirAliases[0].GitRepo)
+	}
+}
+
+func TestDetectGitRepo(t *testing.T) {
+	url := "https://example.com/me/proj.git"
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "proj", Path: newRepoWithOrigin(t, url)},
+			{Alias: "plain", Path: t.TempDir()},
+		},
+	}
+
+	cfg, remote, err := cfg.DetectGitRepo("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remote != url || cfg.DirAliases[0].GitRepo != url {
+		t.Errorf("Expected git repo %q, but got %q", url, cfg.DirAliases[0].GitRepo)
+	}
+
+	_, _, err = cfg.DetectGitRepo("plain")
+	if err == nil {
+		t.Error("Expected an error for a directory without a remote, but got nil")
+	}
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Supported values of C.Multiplexer.
+const (
+	MuxTmux   = "tmux"
+	MuxZellij = "zellij"
+)
+
+// Special entries of MuxOptions.Windows.
+const (
+	WindowEditor = "editor"
+	WindowShell  = "shell"
+)
+
+// DefaultWindows is the layout of sessions for aliases without windows of
+// their own: the editor in the first window and a shell in the second.
+var DefaultWindows = []string{WindowEditor, WindowShell}
+
+// MuxOptions holds the session layout of an alias in multiplexer mode.
+//
+// Windows lists the windows (tabs in zellij) of a new session in order. Each
+// entry is `editor` for the editor, `shell` for a plain shell, or any other
+// shell command to run in the project directory. ZellijLayout is the name or
+// path of a zellij layout to use instead of Windows.
+type MuxOptions struct {
+	Windows      []string `json:"mux_windows,omitempty"`
+	ZellijLayout string   `json:"zellij_layout,omitempty"`
+}
+
+// SetMultiplexer sets the multiplexer that aliases are opened in. An empty
+// multiplexer opens the editor directly.
+func (cfg C) SetMultiplexer(multiplexer string) (C, error) {
+	if !slices.Contains([]string{"", MuxTmux, MuxZellij}, multiplexer) {
+		return cfg, fmt.Errorf("invalid multiplexer `%v` (expected tmux or zellij)", multiplexer)
+	}
+
+	newCfg := cfg
+	newCfg.Multiplexer = multiplexer
+	return newCfg, nil
+}
+
+// SetMuxOptions replaces the session layout of an existing alias.
+func (cfg C) SetMuxOptions(alias string, opts MuxOptions) (C, error) {
+	for _, w := range opts.Windows {
+		if strings.TrimSpace(w) == "" {
+			return cfg, errors.New("windows can't be empty")
+		}
+	}
+
+	return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+		d.MuxOptions = opts
+		return nil
+	})
+}
+
+// keepShell is appended to window commands to start a shell when they exit.
+const keepShell = `; exec "${SHELL:-sh}"`
+
+// SessionName returns the name of the multiplexer session of an alias.
+// Characters that tmux doesn't allow in session names are replaced.
+func SessionName(alias string) string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(alias)
+}
+
+// muxCommands returns the commands that attach to the session of target,
+// creating it in dir first if it doesn't exist. cmds are the commands that
+// open the editor, which run in the `editor` window. Inside a session of the
+// same multiplexer, the client is switched to the session instead of nesting
+// it. Files that have to be written before the commands run are returned
+// with their contents.
+func (cfg C) muxCommands(target DirAlias, dir string, cmds []*exec.Cmd, runner Runner) ([]*exec.Cmd, map[string]string, error) {
+	var editorLine []string
+	for _, cmd := range cmds {
+		editorLine = append(editorLine, cmdLine(cmd))
+	}
+
+	windows := target.Windows
+	if len(windows) == 0 {
+		windows = DefaultWindows
+	}
+
+	// Commands are followed by a shell so that windows stay open (and the
+	// session alive) when they exit
+	var commands []string
+	for _, w := range windows {
+		switch w {
+		case WindowEditor:
+			commands = append(commands, strings.Join(editorLine, " && ")+keepShell)
+		case WindowShell:
+			commands = append(commands, "")
+		default:
+			commands = append(commands, w+keepShell)
+		}
+	}
+
+	name := SessionName(target.Alias)
+	switch cfg.Multiplexer {
+	case MuxTmux:
+		return tmuxCommands(name, dir, windows, commands, runner), nil, nil
+	case MuxZellij:
+		return zellijCommands(name, dir, target.ZellijLayout, windows, commands, runner)
+	}
+
+	return nil, nil, fmt.Errorf("invalid multiplexer `%v` (expected tmux or zellij)", cfg.Multiplexer)
+}
+
+func tmuxCommands(name, dir string, windows, commands []string, runner Runner) []*exec.Cmd {
+	var cmds []*exec.Cmd
+	target := "=" + name
+
+	if runner.Run(exec.Command("tmux", "has-session", "-t", target)) != nil {
+		for i, w := range windows {
+			args := []string{"new-window", "-t", target + ":"}
+			if i == 0 {
+				args = []string{"new-session", "-d", "-s", name}
+			}
+			if dir != "" {
+				args = append(args, "-c", dir)
+			}
+			args = append(args, "-n", windowName(w))
+			if commands[i] != "" {
+				args = append(args, "sh", "-c", commands[i])
+			}
+			cmds = append(cmds, exec.Command("tmux", args...))
+		}
+		cmds = append(cmds, exec.Command("tmux", "select-window", "-t", target+":^"))
+	}
+
+	if os.Getenv("TMUX") != "" {
+		return append(cmds, exec.Command("tmux", "switch-client", "-t", target))
+	}
+	return append(cmds, exec.Command("tmux", "attach-session", "-t", target))
+}
+
+func zellijCommands(name, dir, layout string, windows, commands []string, runner Runner) ([]*exec.Cmd, map[string]string, error) {
+	// zellij has no command to switch sessions from the command line
+	if os.Getenv("ZELLIJ") != "" {
+		return nil, nil, fmt.Errorf("already inside a zellij session; detach first or switch to `%v` with the session manager", name)
+	}
+
+	var out bytes.Buffer
+	list := exec.Command("zellij", "list-sessions", "--short", "--no-formatting")
+	list.Stdout = &out
+	// Listing fails when there are no sessions at all
+	_ = runner.Run(list)
+
+	exists := false
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		exists = exists || strings.TrimSpace(scanner.Text()) == name
+	}
+
+	if exists {
+		return []*exec.Cmd{exec.Command("zellij", "attach", name)}, nil, nil
+	}
+
+	var files map[string]string
+	if layout == "" {
+		var kdl string
+		var err error
+		layout, kdl, err = zellijLayout(name, dir, windows, commands)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = map[string]string{layout: kdl}
+	}
+
+	cmd := exec.Command("zellij", "--session", name, "--layout", layout)
+	cmd.Dir = dir
+	return []*exec.Cmd{cmd}, files, nil
+}
+
+// zellijLayout returns a zellij layout with a tab per window and the path in
+// the user cache directory where it's written to.
+func zellijLayout(name, dir string, windows, commands []string) (string, string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("layout {\n")
+	if dir != "" {
+		fmt.Fprintf(&b, "    cwd %v\n", kdlQuote(dir))
+	}
+	for i, w := range windows {
+		focus := ""
+		if i == 0 {
+			focus = " focus=true"
+		}
+		fmt.Fprintf(&b, "    tab name=%v%v {\n", kdlQuote(windowName(w)), focus)
+		if commands[i] == "" {
+			b.WriteString("        pane\n")
+		} else {
+			fmt.Fprintf(&b, "        pane command=\"sh\" {\n            args \"-c\" %v\n        }\n", kdlQuote(commands[i]))
+		}
+		b.WriteString("    }\n")
+	}
+	b.WriteString("}\n")
+
+	path := filepath.Join(cacheDir, "gopen", "layouts", name+".kdl")
+	return path, b.String(), nil
+}
+
+// windowName returns the name of the window running w: `editor` and `shell`
+// are kept, and commands are named after their program.
+func windowName(w string) string {
+	if w == WindowEditor || w == WindowShell {
+		return w
+	}
+
+	fields := strings.Fields(w)
+	if len(fields) == 0 {
+		return WindowShell
+	}
+	return filepath.Base(fields[0])
+}
+
+func kdlQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// cmdLine returns cmd as a shell line. Windows are started with the
+// environment of the multiplexer, so the variables that cmd adds to ours
+// are set with `env`.
+func cmdLine(cmd *exec.Cmd) string {
+	if cmd.Env == nil {
+		return shellJoin(cmd.Args)
+	}
+
+	environ := map[string]bool{}
+	for _, kv := range os.Environ() {
+		environ[kv] = true
+	}
+
+	var added []string
+	for _, kv := range cmd.Env {
+		if !environ[kv] {
+			added = append(added, kv)
+		}
+	}
+	if len(added) == 0 {
+		return shellJoin(cmd.Args)
+	}
+
+	return shellJoin(append(append([]string{"env"}, added...), cmd.Args...))
+}
+
+// shellJoin quotes args for a POSIX shell, leaving the ones that don't need it
+// as they are.
+func shellJoin(args []string) string {
+	var quoted []string
+	for _, arg := range args {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:+,@%") == "" {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
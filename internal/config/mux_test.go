@@ -0,0 +1,165 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/config/configtest"
+)
+
+const keepShell = `; exec "${SHELL:-sh}"`
+
+func muxConfig(t *testing.T, multiplexer string, windows []string) config.C {
+	t.Helper()
+
+	return config.C{
+		EditorCmd:   "nvim",
+		Multiplexer: multiplexer,
+		DirAliases: []config.DirAlias{
+			{Alias: "my.proj", Path: t.TempDir(), MuxOptions: config.MuxOptions{Windows: windows}},
+		},
+	}
+}
+
+func TestGopenTmuxNewSession(t *testing.T) {
+	t.Setenv("TMUX", "")
+	cfg := muxConfig(t, config.MuxTmux, []string{"editor", "go test ./...", "shell"})
+	dir := cfg.DirAliases[0].Path
+
+	r := &configtest.Runner{Fail: map[string]bool{"tmux has-session -t =my_proj": true}}
+	err := cfg.GopenWith("my.proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"tmux", "has-session", "-t", "=my_proj"},
+		{"tmux", "new-session", "-d", "-s", "my_proj", "-c", dir, "-n", "editor", "sh", "-c", "nvim " + dir + keepShell},
+		{"tmux", "new-window", "-t", "=my_proj:", "-c", dir, "-n", "go", "sh", "-c", "go test ./..." + keepShell},
+		{"tmux", "new-window", "-t", "=my_proj:", "-c", dir, "-n", "shell"},
+		{"tmux", "select-window", "-t", "=my_proj:^"},
+		{"tmux", "attach-session", "-t", "=my_proj"},
+	}
+	if !reflect.DeepEqual(r.Calls, expected) {
+		t.Errorf("Expected\n%q\nbut got\n%q", expected, r.Calls)
+	}
+}
+
+func TestGopenTmuxEditorEnv(t *testing.T) {
+	t.Setenv("TMUX", "")
+	cfg := muxConfig(t, config.MuxTmux, []string{"editor"})
+	cfg.Editors = []config.EditorProfile{{Name: "hx", Command: "hx", Env: map[string]string{"HELIX_RUNTIME": "/rt"}}}
+	cfg.DefaultEditor = "hx"
+	dir := cfg.DirAliases[0].Path
+
+	r := &configtest.Runner{Fail: map[string]bool{"tmux has-session -t =my_proj": true}}
+	err := cfg.GopenWith("my.proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "env HELIX_RUNTIME=/rt hx " + dir + keepShell
+	if len(r.Calls) < 2 || r.Calls[1][len(r.Calls[1])-1] != expected {
+		t.Errorf("Expected the editor window to run %q, but got %q", expected, r.Calls)
+	}
+}
+
+func TestGopenTmuxSwitchClient(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1234,0")
+	cfg := muxConfig(t, config.MuxTmux, nil)
+
+	r := &configtest.Runner{}
+	err := cfg.GopenWith("my.proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"tmux", "has-session", "-t", "=my_proj"},
+		{"tmux", "switch-client", "-t", "=my_proj"},
+	}
+	if !reflect.DeepEqual(r.Calls, expected) {
+		t.Errorf("Expected %q, but got %q", expected, r.Calls)
+	}
+}
+
+func TestGopenZellij(t *testing.T) {
+	t.Setenv("ZELLIJ", "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := muxConfig(t, config.MuxZellij, nil)
+	list := "zellij list-sessions --short --no-formatting"
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout := filepath.Join(cacheDir, "gopen", "layouts", "my_proj.kdl")
+
+	// The layout is only written when the plan is executed
+	plan, err := cfg.Plan("my.proj", config.OpenOptions{Runner: &configtest.Runner{Output: map[string]string{list: "other\n"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plan.Files[layout]; !ok {
+		t.Errorf("Expected the plan to write %v, but got %q", layout, plan.Files)
+	}
+	if _, err := os.Stat(layout); !os.IsNotExist(err) {
+		t.Errorf("Expected %v not to be written while planning, but got %v", layout, err)
+	}
+
+	r := &configtest.Runner{Output: map[string]string{list: "other\n"}}
+	err = cfg.GopenWith("my.proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"zellij", "list-sessions", "--short", "--no-formatting"},
+		{"zellij", "--session", "my_proj", "--layout", layout},
+	}
+	if !reflect.DeepEqual(r.Calls, expected) {
+		t.Errorf("Expected %q, but got %q", expected, r.Calls)
+	}
+
+	kdl, err := os.ReadFile(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(kdl), `tab name="editor" focus=true`) || !strings.Contains(string(kdl), `tab name="shell"`) {
+		t.Errorf("Expected an editor and a shell tab, but got:\n%s", kdl)
+	}
+
+	r = &configtest.Runner{Output: map[string]string{list: "other\nmy_proj\n"}}
+	err = cfg.GopenWith("my.proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := r.Calls[len(r.Calls)-1]; !reflect.DeepEqual(last, []string{"zellij", "attach", "my_proj"}) {
+		t.Errorf("Expected to attach to the existing session, but got %q", last)
+	}
+
+	t.Setenv("ZELLIJ", "0")
+	err = cfg.GopenWith("my.proj", config.OpenOptions{Runner: &configtest.Runner{}})
+	if err == nil {
+		t.Error("Expected an error inside a zellij session, but got nil")
+	}
+}
+
+func TestSetMultiplexer(t *testing.T) {
+	cfg, err := config.C{}.SetMultiplexer(config.MuxZellij)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Multiplexer != config.MuxZellij {
+		t.Errorf("Expected multiplexer zellij, but got %q", cfg.Multiplexer)
+	}
+
+	_, err = cfg.SetMultiplexer("screen")
+	if err == nil {
+		t.Error("Expected an error for an unsupported multiplexer, but got nil")
+	}
+}
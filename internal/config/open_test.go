@@ -0,0 +1,201 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/config/configtest"
+)
+
+// fakeOpen returns OpenOptions made entirely of fakes, with a filesystem
+// holding the directory /code/proj and the file /code/notes.md.
+func fakeOpen() (config.OpenOptions, *configtest.Runner, *bytes.Buffer) {
+	r := &configtest.Runner{}
+	fsys := &configtest.FS{Dirs: []string{"/code", "/code/proj"}, Files: []string{"/code/notes.md"}}
+	out := &bytes.Buffer{}
+	opts := config.OpenOptions{
+		Runner: r,
+		FS:     fsys,
+		Cloner: &configtest.Cloner{FS: fsys},
+		Stdin:  strings.NewReader(""),
+		Stdout: out,
+		Stderr: out,
+		// Clone progress
+		Progress: out,
+	}
+	return opts, r, out
+}
+
+func TestGopenOpensAlias(t *testing.T) {
+	cfg := config.C{
+		EditorCmd: "nvim -p",
+		DirAliases: []config.DirAlias{
+			{Alias: "proj", Path: "/code/proj"},
+			{Alias: "notes", Path: "/code/notes.md", Kind: config.KindFile},
+		},
+	}
+
+	cases := []struct {
+		alias    string
+		expected []string
+		workDir  string
+	}{
+		{"proj", []string{"nvim", "-p", "/code/proj"}, "/code/proj"},
+		{"notes", []string{"nvim", "-p", "/code/notes.md"}, "/code"},
+	}
+
+	for _, c := range cases {
+		opts, r, out := fakeOpen()
+		err := cfg.GopenWith(c.alias, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(r.Calls, [][]string{c.expected}) {
+			t.Errorf("%v: Expected %q, but got %q", c.alias, c.expected, r.Calls)
+		}
+		if r.Cmds[0].Dir != c.workDir {
+			t.Errorf("%v: Expected the editor to start in %v, but got %v", c.alias, c.workDir, r.Cmds[0].Dir)
+		}
+		if r.Cmds[0].Stdout != out || r.Cmds[0].Stdin != opts.Stdin {
+			t.Errorf("%v: Expected the editor to use the given streams", c.alias)
+		}
+	}
+
+	opts, _, _ := fakeOpen()
+	err := cfg.GopenWith("nope", opts)
+	if err == nil {
+		t.Error("Expected an error for a non-existent alias, but got nil")
+	}
+}
+
+func TestGopenCustomBehaviour(t *testing.T) {
+	cfg := config.C{
+		EditorCmd:       "nvim",
+		CustomBehaviour: true,
+		DirAliases: []config.DirAlias{
+			{Alias: "proj", Path: "/code/proj"},
+			{Alias: "notes", Path: "/code/notes.md", Kind: config.KindFile},
+		},
+	}
+
+	expected := map[string][]string{
+		"proj":  {"nvim"},
+		"notes": {"nvim", "/code/notes.md"},
+	}
+	for alias, e := range expected {
+		opts, r, _ := fakeOpen()
+		err := cfg.GopenWith(alias, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(r.Calls, [][]string{e}) {
+			t.Errorf("%v: Expected %q, but got %q", alias, e, r.Calls)
+		}
+	}
+}
+
+func TestGopenClonesMissingRepo(t *testing.T) {
+	cfg := config.C{
+		EditorCmd:  "nvim",
+		DirAliases: []config.DirAlias{{Alias: "new", Path: "/code/new", GitRepo: "https://example.com/new.git"}},
+	}
+
+	opts, r, out := fakeOpen()
+	cloner := opts.Cloner.(*configtest.Cloner)
+	err := cfg.GopenWith("new", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cloner.Cloned) != 1 || cloner.Cloned[0].Alias != "new" {
+		t.Errorf("Expected `new` to be cloned, but got %v", cloner.Cloned)
+	}
+	if out.String() != "Cloning into '/code/new'...\n" {
+		t.Errorf("Expected only the clone progress to be written, but got %q", out.String())
+	}
+	if r.Cmds[0].Dir != "/code/new" {
+		t.Errorf("Expected the editor to start in the clone, but got %v", r.Cmds[0].Dir)
+	}
+	if !reflect.DeepEqual(r.Calls, [][]string{{"nvim", "/code/new"}}) {
+		t.Errorf("Expected the clone to be opened, but got %q", r.Calls)
+	}
+
+	opts, r, _ = fakeOpen()
+	opts.Cloner = &configtest.Cloner{Err: errors.New("authentication required")}
+	err = cfg.GopenWith("new", opts)
+	if err == nil || err.Error() != "authentication required" {
+		t.Errorf("Expected the clone error, but got %v", err)
+	}
+	if len(r.Calls) > 0 {
+		t.Errorf("Expected the editor not to run after a failed clone, but got %q", r.Calls)
+	}
+
+	cfg.DirAliases[0].GitRepo = ""
+	opts, _, _ = fakeOpen()
+	err = cfg.GopenWith("new", opts)
+	if err == nil {
+		t.Error("Expected an error for a missing path without a repo, but got nil")
+	}
+}
+
+func TestGopenRemoteAlias(t *testing.T) {
+	cfg := config.C{
+		EditorCmd:  "nvim",
+		DirAliases: []config.DirAlias{{Alias: "proj", Host: "devvm", Path: "~/proj", GitRepo: "https://example.com/proj.git"}},
+	}
+
+	// Without a Cloner, the remote clone goes through the Runner too
+	opts, r, _ := fakeOpen()
+	opts.Cloner = nil
+	exists := "ssh devvm test -e ~/'proj' && echo exists || echo missing"
+	r.Output = map[string]string{exists: "missing\n"}
+
+	err := cfg.GopenWith("proj", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"ssh", "devvm", "test -e ~/'proj' && echo exists || echo missing"},
+		{"ssh", "devvm", "git clone -- 'https://example.com/proj.git' ~/'proj'"},
+		{"ssh", "-t", "devvm", "cd ~/'proj' && nvim ~/'proj'"},
+	}
+	if !reflect.DeepEqual(r.Calls, expected) {
+		t.Errorf("Expected\n%q\nbut got\n%q", expected, r.Calls)
+	}
+
+	opts, r, _ = fakeOpen()
+	r.Fail = map[string]bool{exists: true}
+	err = cfg.GopenWith("proj", opts)
+	if err == nil || !strings.Contains(err.Error(), "couldn't reach devvm") {
+		t.Errorf("Expected an error for an unreachable host, but got %v", err)
+	}
+}
+
+func TestGopenMatchesRulesInFS(t *testing.T) {
+	cfg := config.C{
+		EditorCmd:   "nvim",
+		EditorRules: []config.EditorRule{{Match: "*.md", Editor: "typora"}},
+		DirAliases: []config.DirAlias{
+			{Alias: "docs", Path: "/code"},
+			{Alias: "proj", Path: "/code/proj"},
+		},
+	}
+
+	expected := map[string]string{"docs": "typora", "proj": "nvim"}
+	for alias, e := range expected {
+		opts, r, _ := fakeOpen()
+		err := cfg.GopenWith(alias, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Calls) != 1 || r.Calls[0][0] != e {
+			t.Errorf("%v: Expected %v to open the alias, but got %q", alias, e, r.Calls)
+		}
+	}
+}
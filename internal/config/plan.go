@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Plan is everything that opening an alias does, as worked out by C.Plan. It
+// can be printed to explain what `gopen` would do, or executed with
+// Plan.Exec.
+type Plan struct {
+	Alias DirAlias
+	// Editor is the editor that opens the alias, and EditorSource is where
+	// it came from, as returned by C.ResolveEditor
+	Editor       EditorProfile
+	EditorSource string
+	// Clone is true if the git repo of the alias is cloned into its path
+	// (on its host for remote aliases) before anything else
+	Clone bool
+	// WorkDir is the local directory that the editor or the multiplexer
+	// session starts in, and is empty for remote aliases
+	WorkDir string
+	// Detach is true if the last command is started in the background with
+	// its output appended to LogPath
+	Detach  bool
+	LogPath string
+	// Files are written before the commands run, mapped to their contents,
+	// like the layout of a new zellij session
+	Files map[string]string
+	// Commands are run in order: the ones before the last prepare the
+	// editor (e.g., start a container or a multiplexer session) and the last
+	// one opens it
+	Commands []*exec.Cmd
+}
+
+// Plan works out how GopenWith would open targetAlias without opening it.
+// Only read-only checks are run while planning, like whether a remote path
+// exists or a multiplexer session is already running, and they go through
+// opts.Runner and opts.FS.
+func (cfg C) Plan(targetAlias string, opts OpenOptions) (Plan, error) {
+	opts = opts.withDefaults()
+	runner := opts.Runner
+
+	var target DirAlias
+	for _, dirAlias := range cfg.DirAliases {
+		if targetAlias == dirAlias.Alias {
+			target = dirAlias
+			break
+		}
+	}
+
+	targetPath := target.Path
+	if targetPath == "" {
+		return Plan{}, errors.New("Invalid command or non-existent alias\nRun `gopen help` for info")
+	}
+
+	editor, source, err := cfg.ResolveEditorWith(target.Alias, opts)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{Alias: target, Editor: editor, EditorSource: source}
+
+	if target.IsRemote() {
+		if target.GitRepo != "" {
+			exists, err := target.RemoteExists(runner)
+			if err != nil {
+				return Plan{}, err
+			}
+			plan.Clone = !exists
+		}
+
+		cmd := target.RemoteCommand(editor.Argv(), editor.envList(), cfg.CustomBehaviour)
+		return cfg.planLaunch(plan, []*exec.Cmd{cmd}, runner)
+	}
+
+	// A path that will be cloned is a directory
+	isDir := true
+	info, err := opts.FS.Stat(targetPath)
+	switch {
+	case err == nil:
+		isDir = info.IsDir()
+	case os.IsNotExist(err) && target.GitRepo != "":
+		plan.Clone = true
+	default:
+		return Plan{}, err
+	}
+
+	// File aliases are opened from the directory containing the file
+	plan.WorkDir = targetPath
+	if !isDir {
+		plan.WorkDir = filepath.Dir(targetPath)
+	}
+
+	if target.Container != "" {
+		cmds, err := target.ContainerCommands(editor.Argv(), editor.envList(), cfg.CustomBehaviour)
+		if err != nil {
+			return Plan{}, err
+		}
+		return cfg.planLaunch(plan, cmds, runner)
+	}
+
+	var cmd *exec.Cmd
+	// CustomBehaviour lets the user open the target path in a new buffer.
+	// Files are always passed to the editor since there's nothing else to
+	// open.
+	if cfg.CustomBehaviour && isDir {
+		cmd = editor.command()
+	} else {
+		cmd = editor.command(targetPath)
+	}
+	// Multiplexer sessions are started in WorkDir by the multiplexer
+	// instead
+	cmd.Dir = plan.WorkDir
+
+	return cfg.planLaunch(plan, []*exec.Cmd{cmd}, runner)
+}
+
+// planLaunch completes plan with the commands that open its alias: cmds as
+// they are, or, in multiplexer mode, the commands of a session where they run
+// in the editor window. Outside multiplexers, the last command is detached
+// if the detach mode says so.
+func (cfg C) planLaunch(plan Plan, cmds []*exec.Cmd, runner Runner) (Plan, error) {
+	if cfg.Multiplexer != "" {
+		var err error
+		cmds, plan.Files, err = cfg.muxCommands(plan.Alias, plan.WorkDir, cmds, runner)
+		if err != nil {
+			return Plan{}, err
+		}
+	} else if shouldDetach(plan.Alias, plan.Editor, cmds[len(cmds)-1]) {
+		logPath, err := LogPath(plan.Alias.Alias)
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Detach = true
+		plan.LogPath = logPath
+	}
+
+	plan.Commands = cmds
+	return plan, nil
+}
+
+// Exec carries out the plan with the runner, cloner, and streams of opts.
+// Its Editor is ignored since it's already part of the plan. Nothing is
+// printed besides the output of the commands and the clone progress, so
+// callers that want to say what's going on can do so from the plan.
+func (p Plan) Exec(opts OpenOptions) error {
+	opts = opts.withDefaults()
+
+	if p.Clone {
+		err := opts.Cloner.Clone(p.Alias, opts.Progress)
+		if err != nil {
+			return err
+		}
+	}
+
+	for path, contents := range p.Files {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(path, []byte(contents), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	cmds := p.Commands
+	err := runAll(cmds[:len(cmds)-1], opts.Runner, opts.Stderr)
+	if err != nil {
+		return err
+	}
+
+	last := cmds[len(cmds)-1]
+	if p.Detach {
+		return detach(p.Alias.Alias, last, opts.Runner)
+	}
+
+	last.Stdin = opts.Stdin
+	last.Stdout = opts.Stdout
+	last.Stderr = opts.Stderr
+	return opts.Runner.Run(last)
+}
+
+// runAll runs the commands that prepare the editor, like starting a
+// container, sending their output to w instead of the editor's stdout.
+func runAll(cmds []*exec.Cmd, runner Runner, w io.Writer) error {
+	for _, cmd := range cmds {
+		cmd.Stdout = w
+		cmd.Stderr = w
+
+		err := runner.Run(cmd)
+		if err != nil {
+			return fmt.Errorf("%v: %v", strings.Join(cmd.Args, " "), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/config/configtest"
+)
+
+func TestPlanDoesNotExecute(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "proj")
+	cfg := config.C{
+		EditorCmd: "code -n",
+		DirAliases: []config.DirAlias{
+			{Alias: "proj", Path: missing, GitRepo: "https://example.com/proj.git"},
+		},
+	}
+
+	r := &configtest.Runner{}
+	plan, err := cfg.Plan("proj", config.OpenOptions{Runner: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !plan.Clone {
+		t.Error("Expected the missing repo to be cloned")
+	}
+	if plan.WorkDir != missing {
+		t.Errorf("Expected workdir %v, but got %v", missing, plan.WorkDir)
+	}
+	if !plan.Detach || plan.LogPath == "" {
+		t.Errorf("Expected code to be detached with a log file, but got %v and %q", plan.Detach, plan.LogPath)
+	}
+	if plan.EditorSource != config.EditorFromConfig {
+		t.Errorf("Expected the editor to come from the config, but got %v", plan.EditorSource)
+	}
+
+	expected := []string{"code", "-n", missing}
+	if len(plan.Commands) != 1 || !reflect.DeepEqual(plan.Commands[0].Args, expected) {
+		t.Errorf("Expected the command %q, but got %v", expected, plan.Commands)
+	}
+
+	if len(r.Calls) > 0 || len(r.Started) > 0 {
+		t.Errorf("Expected nothing to run, but got %q", r.Calls)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("Expected %v not to be created, but got %v", missing, err)
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// remoteEditors are editors that can open a folder on an SSH host by
+// themselves, mapped to the arguments that do so. `%h` is replaced with the
+// host and `%p` with the path.
+var remoteEditors = map[string][]string{
+	"code":          {"--remote", "ssh-remote+%h", "%p"},
+	"code-insiders": {"--remote", "ssh-remote+%h", "%p"},
+	"codium":        {"--remote", "ssh-remote+%h", "%p"},
+	"cursor":        {"--remote", "ssh-remote+%h", "%p"},
+}
+
+// AddRemoteAlias works like AddAlias but for a project on an SSH host. path is
+// used as-is on the host, so it must be absolute or start with `~/`. Nothing
+// about the path is detected since it isn't available locally. If the alias
+// already exists, its container options are cleared.
+func (cfg C) AddRemoteAlias(alias, host, path string) (C, error) {
+	err := validateAlias(alias)
+	if err != nil {
+		return cfg, err
+	}
+
+	err = validateRemote(host, path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if cfg.hasAlias(alias) {
+		return cfg.UpdateAlias(alias, func(d *DirAlias) error {
+			d.Host = host
+			d.Path = path
+			d.Kind = ""
+			// Containers can't be used with remote aliases
+			d.ContainerOptions = ContainerOptions{}
+			return nil
+		})
+	}
+
+	newCfg := cfg
+	newCfg.DirAliases = append(slices.Clip(cfg.DirAliases), DirAlias{Alias: alias, Host: host, Path: path})
+	return newCfg, nil
+}
+
+func validateRemote(host, p string) error {
+	if host == "" || strings.HasPrefix(host, "-") || strings.ContainsAny(host, " \t\n") {
+		return fmt.Errorf("Error: invalid host `%v`", host)
+	}
+
+	if p != "~" && !strings.HasPrefix(p, "~/") && !path.IsAbs(p) {
+		return fmt.Errorf("Error: remote path `%v` must be absolute or start with `~/`", p)
+	}
+
+	return nil
+}
+
+// IsRemote reports whether the alias points to a project on an SSH host.
+func (d DirAlias) IsRemote() bool {
+	return d.Host != ""
+}
+
+// Location returns the path of the alias, prefixed with `host:` if it's
+// remote.
+func (d DirAlias) Location() string {
+	if d.IsRemote() {
+		return d.Host + ":" + d.Path
+	}
+	return d.Path
+}
+
+// RemoteCommand returns the command that opens the remote project of the
+// alias with editor, the editor command and its arguments. Editors with a
+// remote mode of their own (like VS Code's `--remote ssh-remote+host`) run
+// locally; any other editor runs on the host through
+// `ssh -t host 'cd path && editor path'`. env holds KEY=VALUE pairs, set on
+// the local command or with `env` on the host. As with local aliases,
+// customBehaviour leaves the path out of the editor command.
+func (d DirAlias) RemoteCommand(editor, env []string, customBehaviour bool) *exec.Cmd {
+	if len(editor) > 0 {
+		if args, ok := remoteEditors[filepath.Base(editor[0])]; ok {
+			r := strings.NewReplacer("%h", d.Host, "%p", d.Path)
+			argv := slices.Clip(editor)
+			for _, arg := range args {
+				argv = append(argv, r.Replace(arg))
+			}
+
+			cmd := exec.Command(argv[0], argv[1:]...)
+			if len(env) > 0 {
+				cmd.Env = append(os.Environ(), env...)
+			}
+			return cmd
+		}
+	}
+
+	script := "cd " + remoteQuote(d.Path)
+	if len(editor) > 0 {
+		if len(env) > 0 {
+			editor = append(append([]string{"env"}, env...), editor...)
+		}
+		script += " && " + shellJoin(editor)
+		if !customBehaviour {
+			script += " " + remoteQuote(d.Path)
+		}
+	}
+
+	return exec.Command("ssh", "-t", d.Host, script)
+}
+
+// RemoteExists reports whether the path of the alias exists on its host. The
+// ssh command is run with runner.
+func (d DirAlias) RemoteExists(runner Runner) (bool, error) {
+	var out bytes.Buffer
+	cmd := exec.Command("ssh", d.Host, "test -e "+remoteQuote(d.Path)+" && echo exists || echo missing")
+	cmd.Stdout = &out
+
+	err := runner.Run(cmd)
+	if err != nil {
+		return false, fmt.Errorf("couldn't reach %v: %v", d.Host, err)
+	}
+
+	switch strings.TrimSpace(out.String()) {
+	case "exists":
+		return true, nil
+	case "missing":
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected output from %v: %q", d.Host, out.String())
+}
+
+// cloneRemote clones the alias' git repo into its path on the host using the
+// host's git. Auth isn't used since the host authenticates with its own
+// credentials. The ssh command is run with runner.
+func (d DirAlias) cloneRemote(runner Runner, progress io.Writer) error {
+	script := "git clone"
+	if d.Branch != "" {
+		script += " --branch " + remoteQuote(d.Branch)
+	}
+	if d.Depth > 0 {
+		script += " --depth " + strconv.Itoa(d.Depth)
+	}
+	if d.Submodules {
+		script += " --recurse-submodules"
+	}
+	script += " -- " + remoteQuote(d.GitRepo) + " " + remoteQuote(d.Path)
+
+	var out bytes.Buffer
+	cmd := exec.Command("ssh", d.Host, script)
+	if progress != nil {
+		cmd.Stdout, cmd.Stderr = progress, progress
+	} else {
+		cmd.Stdout, cmd.Stderr = &out, &out
+	}
+
+	err := runner.Run(cmd)
+	if err != nil {
+		msg := strings.TrimSpace(out.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("git clone on %v failed: %v", d.Host, msg)
+	}
+
+	return nil
+}
+
+// remoteQuote quotes s for the host's POSIX shell. A leading `~/` is left
+// unquoted so that the shell expands it to the home directory.
+func remoteQuote(s string) string {
+	prefix := ""
+	if s == "~" {
+		return s
+	}
+	if strings.HasPrefix(s, "~/") {
+		prefix, s = "~/", s[2:]
+	}
+
+	return prefix + "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
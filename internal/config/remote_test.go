@@ -0,0 +1,166 @@
+package config_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/config/configtest"
+)
+
+// stubSSH puts an `ssh` script first in PATH that runs the remote command
+// locally, as if every host were this machine. Its arguments are appended to
+// the returned log file.
+func stubSSH(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "ssh.log")
+	script := `#!/bin/sh
+echo "$@" >> "` + log + `"
+while [ "${1#-}" != "$1" ]; do shift; done
+shift
+exec sh -c "$*"
+`
+	err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestAddRemoteAlias(t *testing.T) {
+	cfg, err := config.C{}.AddRemoteAlias("proj", "devvm", "~/code/proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := config.DirAlias{Alias: "proj", Host: "devvm", Path: "~/code/proj"}
+	if !reflect.DeepEqual(cfg.DirAliases[0], expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, cfg.DirAliases[0])
+	}
+	if cfg.DirAliases[0].Location() != "devvm:~/code/proj" {
+		t.Errorf("Expected location devvm:~/code/proj, but got %v", cfg.DirAliases[0].Location())
+	}
+
+	cfg, err = cfg.MoveAlias("proj", "/srv/proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DirAliases[0].Path != "/srv/proj" {
+		t.Errorf("Expected the remote path to be kept as-is, but got %v", cfg.DirAliases[0].Path)
+	}
+
+	for _, c := range [][2]string{{"devvm", "code/proj"}, {"", "/srv/proj"}, {"-oProxyCommand=x", "/srv/proj"}} {
+		_, err = config.C{}.AddRemoteAlias("proj", c[0], c[1])
+		if err == nil {
+			t.Errorf("Expected an error for host %q and path %q, but got nil", c[0], c[1])
+		}
+	}
+}
+
+func TestRemoteCommand(t *testing.T) {
+	d := config.DirAlias{Alias: "proj", Host: "devvm", Path: "~/it's here"}
+
+	cases := []struct {
+		editor          []string
+		customBehaviour bool
+		expected        []string
+	}{
+		{[]string{"nvim"}, false, []string{"ssh", "-t", "devvm", `cd ~/'it'\''s here' && nvim ~/'it'\''s here'`}},
+		{[]string{"nvim"}, true, []string{"ssh", "-t", "devvm", `cd ~/'it'\''s here' && nvim`}},
+		{[]string{"nvim", "-c", "set nu"}, true, []string{"ssh", "-t", "devvm", `cd ~/'it'\''s here' && nvim -c 'set nu'`}},
+		{[]string{"code", "-n"}, false, []string{"code", "-n", "--remote", "ssh-remote+devvm", "~/it's here"}},
+		{[]string{"code", "--profile", "My Work"}, false, []string{"code", "--profile", "My Work", "--remote", "ssh-remote+devvm", "~/it's here"}},
+	}
+
+	for _, c := range cases {
+		actual := d.RemoteCommand(c.editor, nil, c.customBehaviour).Args
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%q: Expected %q, but got %q", c.editor, c.expected, actual)
+		}
+	}
+}
+
+func TestPlanRemoteEditorEnv(t *testing.T) {
+	env := map[string]string{"HELIX_RUNTIME": "/rt"}
+	cfg := config.C{
+		Editors: []config.EditorProfile{
+			{Name: "hx", Command: "hx", Env: env},
+			{Name: "code", Command: "code", Env: env},
+		},
+		DirAliases: []config.DirAlias{{Alias: "proj", Host: "devvm", Path: "/srv/proj"}},
+	}
+
+	// The environment is set in the remote shell
+	plan, err := cfg.Plan("proj", config.OpenOptions{Editor: "hx", Runner: &configtest.Runner{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ssh", "-t", "devvm", "cd '/srv/proj' && env HELIX_RUNTIME=/rt hx '/srv/proj'"}
+	if !reflect.DeepEqual(plan.Commands[0].Args, expected) {
+		t.Errorf("Expected %q, but got %q", expected, plan.Commands[0].Args)
+	}
+
+	// Editors with a remote mode run locally with the environment
+	plan, err = cfg.Plan("proj", config.OpenOptions{Editor: "code", Runner: &configtest.Runner{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(plan.Commands[0].Env, "HELIX_RUNTIME=/rt") {
+		t.Errorf("Expected the local command to have the environment of the editor, but got %q", plan.Commands[0].Env)
+	}
+}
+
+func TestRemoteCloneAndSync(t *testing.T) {
+	bare := newBareRepo(t)
+	log := stubSSH(t)
+
+	d := config.DirAlias{
+		Alias:      "proj",
+		Host:       "devvm",
+		Path:       filepath.Join(t.TempDir(), "proj"),
+		GitRepo:    bare,
+		GitOptions: config.GitOptions{Branch: "dev", Depth: 1},
+	}
+	cfg := config.C{DirAliases: []config.DirAlias{d}}
+
+	missing, unreachable := cfg.MissingRepos()
+	if len(missing) != 1 || len(unreachable) > 0 {
+		t.Fatalf("Expected the remote repo to be missing, but got %v and %v", missing, unreachable)
+	}
+
+	err := d.Clone(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(filepath.Join(d.Path, "dev.txt"))
+	if err != nil {
+		t.Errorf("Expected dev.txt to be cloned: %v", err)
+	}
+
+	missing, _ = cfg.MissingRepos()
+	if len(missing) != 0 {
+		t.Errorf("Expected no missing repos after cloning, but got %v", missing)
+	}
+
+	calls, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "devvm git clone --branch 'dev' --depth 1") {
+		t.Errorf("Expected git clone to run on devvm, but got calls:\n%s", calls)
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// EditorRule picks the editor of the aliases whose project root has a file
+// matching Match, which is a marker file name (e.g., `pubspec.yaml`) or a glob
+// (e.g., `*.sln`) relative to the root. Editor is an editor profile or
+// command.
+type EditorRule struct {
+	Match  string `json:"match"`
+	Editor string `json:"editor"`
+}
+
+// AddEditorRule adds an editor rule after the existing ones, or replaces the
+// rule with the same Match in place.
+func (cfg C) AddEditorRule(rule EditorRule) (C, error) {
+	if rule.Match == "" || rule.Editor == "" {
+		return cfg, errors.New("editor rules need a match and an editor")
+	}
+
+	_, err := filepath.Match(rule.Match, "")
+	if err != nil {
+		return cfg, fmt.Errorf("invalid pattern `%v`: %v", rule.Match, err)
+	}
+
+	newCfg := cfg
+	i := slices.IndexFunc(cfg.EditorRules, func(r EditorRule) bool { return r.Match == rule.Match })
+	if i == -1 {
+		newCfg.EditorRules = append(slices.Clip(cfg.EditorRules), rule)
+	} else {
+		newCfg.EditorRules = slices.Clone(cfg.EditorRules)
+		newCfg.EditorRules[i] = rule
+	}
+
+	return newCfg, nil
+}
+
+// RemoveEditorRule removes the editor rule with the given match.
+func (cfg C) RemoveEditorRule(match string) (C, error) {
+	i := slices.IndexFunc(cfg.EditorRules, func(r EditorRule) bool { return r.Match == match })
+	if i == -1 {
+		return cfg, fmt.Errorf("there's no editor rule for `%v`", match)
+	}
+
+	newCfg := cfg
+	newCfg.EditorRules = slices.Delete(slices.Clone(cfg.EditorRules), i, i+1)
+	return newCfg, nil
+}
+
+// MatchEditorRule returns the first editor rule that matches the project root
+// of d, along with the paths of the files it matched. The root of a file
+// alias is the file's directory, and remote aliases never match since their
+// files aren't on this machine. Files are looked up in fsys.
+func (cfg C) MatchEditorRule(d DirAlias, fsys FS) (EditorRule, []string, bool) {
+	if d.IsRemote() || len(cfg.EditorRules) == 0 {
+		return EditorRule{}, nil, false
+	}
+
+	root := d.Path
+	if info, err := fsys.Stat(root); err == nil && !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+
+	for _, rule := range cfg.EditorRules {
+		// The pattern was validated when the rule was added
+		matches, _ := fsys.Glob(filepath.Join(root, rule.Match))
+		if len(matches) > 0 {
+			return rule, matches, true
+		}
+	}
+
+	return EditorRule{}, nil, false
+}
@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+func TestEditorRules(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+
+	dotnet := t.TempDir()
+	flutter := t.TempDir()
+	plain := t.TempDir()
+	for _, path := range []string{filepath.Join(dotnet, "App.sln"), filepath.Join(flutter, "pubspec.yaml")} {
+		err := os.WriteFile(path, nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.C{
+		EditorCmd: "nvim",
+		DirAliases: []config.DirAlias{
+			{Alias: "dotnet", Path: dotnet},
+			{Alias: "flutter", Path: flutter},
+			{Alias: "plain", Path: plain},
+			{Alias: "pinned", Path: dotnet, Editor: "code"},
+			{Alias: "sln", Path: filepath.Join(dotnet, "App.sln"), Kind: config.KindFile},
+		},
+	}
+
+	var err error
+	for _, rule := range []config.EditorRule{{"*.sln", "rider"}, {"pubspec.yaml", "studio"}} {
+		cfg, err = cfg.AddEditorRule(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string][2]string{
+		"dotnet":  {"rider", config.EditorFromRule},
+		"flutter": {"studio", config.EditorFromRule},
+		"plain":   {"nvim", config.EditorFromConfig},
+		"pinned":  {"code", config.EditorFromAlias},
+		"sln":     {"rider", config.EditorFromRule},
+	}
+	for alias, e := range expected {
+		editor, source, err := cfg.ResolveEditor(alias, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if editor.Command != e[0] || source != e[1] {
+			t.Errorf("%v: Expected %v from %v, but got %v from %v", alias, e[0], e[1], editor.Command, source)
+		}
+	}
+
+	_, matches, _ := cfg.MatchEditorRule(cfg.DirAliases[0], config.DefaultFS)
+	if !reflect.DeepEqual(matches, []string{filepath.Join(dotnet, "App.sln")}) {
+		t.Errorf("Expected App.sln to be matched, but got %v", matches)
+	}
+
+	cfg, err = cfg.AddEditorRule(config.EditorRule{Match: "*.sln", Editor: "vs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = cfg.RemoveEditorRule("pubspec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRules := []config.EditorRule{{Match: "*.sln", Editor: "vs"}}
+	if !reflect.DeepEqual(cfg.EditorRules, expectedRules) {
+		t.Errorf("Expected %v, but got %v", expectedRules, cfg.EditorRules)
+	}
+
+	_, err = cfg.AddEditorRule(config.EditorRule{Match: "[", Editor: "vs"})
+	if err == nil {
+		t.Error("Expected an error for an invalid pattern, but got nil")
+	}
+	_, err = cfg.RemoveEditorRule("nope")
+	if err == nil {
+		t.Error("Expected an error for a missing rule, but got nil")
+	}
+}
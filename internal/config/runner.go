@@ -0,0 +1,76 @@
+package config
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Runner runs the commands that Gopen launches. It exists so that tests can
+// record the commands instead of running them. Run waits for the command to
+// finish while Start only starts it, for detached editors.
+type Runner interface {
+	Run(cmd *exec.Cmd) error
+	Start(cmd *exec.Cmd) error
+}
+
+type execRunner struct{}
+
+func (execRunner) Run(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
+
+func (execRunner) Start(cmd *exec.Cmd) error {
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// DefaultRunner runs commands as they are.
+var DefaultRunner Runner = execRunner{}
+
+// FS is the part of the filesystem that Gopen looks at to open an alias.
+// Glob works like filepath.Glob.
+type FS interface {
+	Stat(name string) (fs.FileInfo, error)
+	Glob(pattern string) ([]string, error)
+}
+
+type osFS struct{}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+// DefaultFS is the filesystem of the OS.
+var DefaultFS FS = osFS{}
+
+// Cloner clones the git repo of an alias into its path, writing progress
+// messages to progress.
+type Cloner interface {
+	Clone(d DirAlias, progress io.Writer) error
+}
+
+// gitCloner clones local aliases with go-git and remote ones with git on
+// their host, over ssh commands that go through runner.
+type gitCloner struct {
+	runner Runner
+}
+
+func (c gitCloner) Clone(d DirAlias, progress io.Writer) error {
+	if d.IsRemote() {
+		return d.cloneRemote(c.runner, progress)
+	}
+	return d.Clone(progress)
+}
+
+// DefaultCloner clones with DirAlias.Clone.
+var DefaultCloner Cloner = gitCloner{runner: DefaultRunner}
@@ -0,0 +1,246 @@
+package config_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// fillNonZero sets every field reachable from v (which must be settable) to a
+// non-zero value, so that tests cover fields added to the config in the
+// future without having to be updated.
+func fillNonZero(t *testing.T, v reflect.Value) {
+	t.Helper()
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x-" + v.Type().Name())
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(7)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(7)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fillNonZero(t, v.Field(i))
+		}
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fillNonZero(t, s.Index(0))
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		key := reflect.New(v.Type().Key()).Elem()
+		fillNonZero(t, key)
+		val := reflect.New(v.Type().Elem()).Elem()
+		fillNonZero(t, val)
+		m.SetMapIndex(key, val)
+		v.Set(m)
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		fillNonZero(t, p.Elem())
+		v.Set(p)
+	default:
+		t.Fatalf("fillNonZero: unsupported kind %v", v.Kind())
+	}
+}
+
+// fullConfig returns a config where every field, including every field of
+// its aliases, is set. The alias to be changed is `target`.
+func fullConfig(t *testing.T) config.C {
+	t.Helper()
+
+	var cfg config.C
+	fillNonZero(t, reflect.ValueOf(&cfg).Elem())
+
+	var target, other config.DirAlias
+	fillNonZero(t, reflect.ValueOf(&target).Elem())
+	fillNonZero(t, reflect.ValueOf(&other).Elem())
+	target.Alias = "target"
+	target.Path = "/path/to/target"
+	other.Alias = "other"
+	other.Path = "/path/to/other"
+	cfg.DirAliases = []config.DirAlias{target, other}
+
+	return cfg
+}
+
+func TestMutationsKeepUntouchedFields(t *testing.T) {
+	newDir := t.TempDir()
+	cases := []struct {
+		name   string
+		mutate func(config.C) (config.C, error)
+		// change applies the expected change to a copy of the original
+		change func(*config.C)
+	}{
+		{
+			name: "AddAlias overwrite",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.AddAlias("target", "/new/path")
+			},
+			change: func(cfg *config.C) {
+				// The kind is re-recorded, /new/path doesn't exist, and the
+				// alias becomes local
+				cfg.DirAliases[0].Path = "/new/path"
+				cfg.DirAliases[0].Kind = ""
+				cfg.DirAliases[0].Host = ""
+			},
+		},
+		{
+			name: "AddRemoteAlias overwrite",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.AddRemoteAlias("target", "devvm", "~/proj")
+			},
+			change: func(cfg *config.C) {
+				cfg.DirAliases[0].Host = "devvm"
+				cfg.DirAliases[0].Path = "~/proj"
+				cfg.DirAliases[0].Kind = ""
+				cfg.DirAliases[0].ContainerOptions = config.ContainerOptions{}
+			},
+		},
+		{
+			name: "AddAlias new",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.AddAlias("added", "/new/path")
+			},
+			change: func(cfg *config.C) {
+				cfg.DirAliases = append(cfg.DirAliases, config.DirAlias{Alias: "added", Path: "/new/path"})
+			},
+		},
+		{
+			name: "RemoveAlias",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.RemoveAlias("target")
+			},
+			change: func(cfg *config.C) { cfg.DirAliases = cfg.DirAliases[1:] },
+		},
+		{
+			name: "RenameAlias",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.RenameAlias("target", "renamed")
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].Alias = "renamed" },
+		},
+		{
+			name: "MoveAlias",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.MoveAlias("target", "/new/path")
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].Path = "/new/path" },
+		},
+		{
+			name: "MoveAlias local",
+			mutate: func(cfg config.C) (config.C, error) {
+				cfg, err := cfg.UpdateAlias("target", func(d *config.DirAlias) error {
+					d.Host = ""
+					return nil
+				})
+				if err != nil {
+					return cfg, err
+				}
+				return cfg.MoveAlias("target", newDir)
+			},
+			change: func(cfg *config.C) {
+				// The kind is re-recorded for the new path
+				cfg.DirAliases[0].Host = ""
+				cfg.DirAliases[0].Path = newDir
+				cfg.DirAliases[0].Kind = config.KindDir
+			},
+		},
+		{
+			name: "SetGitRepo",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.SetGitRepo("target", "git@example.com:new.git")
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].GitRepo = "git@example.com:new.git" },
+		},
+		{
+			name: "SetGitOptions",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.SetGitOptions("target", config.GitOptions{Branch: "main"})
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].GitOptions = config.GitOptions{Branch: "main"} },
+		},
+		{
+			name: "SetContainerOptions",
+			mutate: func(cfg config.C) (config.C, error) {
+				// fullConfig's aliases are remote, which can't have containers
+				cfg, _ = cfg.UpdateAlias("target", func(d *config.DirAlias) error {
+					d.Host = ""
+					return nil
+				})
+				return cfg.SetContainerOptions("target", config.ContainerOptions{Container: "devcontainer"})
+			},
+			change: func(cfg *config.C) {
+				cfg.DirAliases[0].Host = ""
+				cfg.DirAliases[0].ContainerOptions = config.ContainerOptions{Container: "devcontainer"}
+			},
+		},
+		{
+			name: "SetMuxOptions",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.SetMuxOptions("target", config.MuxOptions{Windows: []string{"shell"}})
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].MuxOptions = config.MuxOptions{Windows: []string{"shell"}} },
+		},
+		{
+			name: "SetAliasDetach",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.SetAliasDetach("target", config.DetachNever)
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].Detach = config.DetachNever },
+		},
+		{
+			name: "SetAliasEditor",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.SetAliasEditor("target", "code -n")
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].Editor = "code -n" },
+		},
+		{
+			name: "SetTags",
+			mutate: func(cfg config.C) (config.C, error) {
+				return cfg.SetTags("target", []string{"new"})
+			},
+			change: func(cfg *config.C) { cfg.DirAliases[0].Tags = []string{"new"} },
+		},
+	}
+
+	for _, c := range cases {
+		original := fullConfig(t)
+		actual, err := c.mutate(original)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.name, err)
+			continue
+		}
+
+		expected := fullConfig(t)
+		c.change(&expected)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%v: Expected\n%+v\nbut got\n%+v", c.name, expected, actual)
+		}
+
+		// The original config must not be modified through shared slices
+		if !reflect.DeepEqual(original, fullConfig(t)) {
+			t.Errorf("%v: the original config was modified: %+v", c.name, original)
+		}
+	}
+}
+
+func TestUpdateAliasError(t *testing.T) {
+	cfg := fullConfig(t)
+
+	_, err := cfg.UpdateAlias("nope", func(*config.DirAlias) error { return nil })
+	if err == nil {
+		t.Error("Expected an error for a missing alias, but got nil")
+	}
+
+	_, err = cfg.RemoveAlias("nope")
+	if err == nil {
+		t.Error("Expected an error for a missing alias, but got nil")
+	}
+}
@@ -0,0 +1,107 @@
+// Package discover finds project roots on disk so that they can be added as
+// Gopen aliases in bulk.
+package discover
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// Markers are the files and directories whose presence marks a directory as a
+// project root.
+var Markers = []string{
+	".git",
+	"go.mod",
+	"package.json",
+	"Cargo.toml",
+	"pyproject.toml",
+	"setup.py",
+	"pom.xml",
+	"build.gradle",
+	"Gemfile",
+	"composer.json",
+	"mix.exs",
+	"pubspec.yaml",
+	"CMakeLists.txt",
+}
+
+// skipped are directories that are never searched for projects.
+var skipped = []string{"node_modules", "vendor", "target", "dist", "build"}
+
+// Project is a project root found by Scan.
+type Project struct {
+	Path    string
+	Markers []string
+	GitRepo string
+}
+
+// Scan walks root looking for project roots at most depth levels below it
+// (the root itself is level 0). Directories inside a project root, hidden
+// directories, and common dependency or build directories aren't searched.
+func Scan(root string, depth int) ([]Project, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped rather than aborting the scan
+			if path != root && d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != root && (strings.HasPrefix(d.Name(), ".") || slices.Contains(skipped, d.Name())) {
+			return fs.SkipDir
+		}
+
+		markers := findMarkers(path)
+		if len(markers) > 0 {
+			// Detection is best-effort, so errors only mean no remote
+			remote, _ := config.DetectRemote(path)
+			projects = append(projects, Project{Path: path, Markers: markers, GitRepo: remote})
+			return fs.SkipDir
+		}
+
+		if level(root, path) >= depth {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+
+	return projects, err
+}
+
+func findMarkers(dir string) []string {
+	var found []string
+	for _, marker := range Markers {
+		_, err := os.Stat(filepath.Join(dir, marker))
+		if err == nil {
+			found = append(found, marker)
+		}
+	}
+
+	return found
+}
+
+func level(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
@@ -0,0 +1,72 @@
+package discover_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/discover"
+)
+
+func mkfile(t *testing.T, path string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	mkfile(t, filepath.Join(root, "api", "go.mod"))
+	mkfile(t, filepath.Join(root, "api", "tools", "package.json")) // nested in a project
+	mkfile(t, filepath.Join(root, "web", "package.json"))
+	mkfile(t, filepath.Join(root, "web", ".git", "HEAD"))
+	mkfile(t, filepath.Join(root, "clients", "rust", "Cargo.toml"))
+	mkfile(t, filepath.Join(root, "deep", "er", "still", "go.mod")) // beyond depth
+	mkfile(t, filepath.Join(root, ".hidden", "go.mod"))
+	mkfile(t, filepath.Join(root, "node_modules", "dep", "package.json"))
+	mkfile(t, filepath.Join(root, "notes", "todo.txt"))
+
+	projects, err := discover.Scan(root, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []discover.Project{
+		{Path: filepath.Join(root, "api"), Markers: []string{"go.mod"}},
+		{Path: filepath.Join(root, "clients", "rust"), Markers: []string{"Cargo.toml"}},
+		{Path: filepath.Join(root, "web"), Markers: []string{".git", "package.json"}},
+	}
+	if !reflect.DeepEqual(projects, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, projects)
+	}
+}
+
+func TestScanRootIsProject(t *testing.T) {
+	root := t.TempDir()
+	mkfile(t, filepath.Join(root, "go.mod"))
+	mkfile(t, filepath.Join(root, "sub", "go.mod"))
+
+	projects, err := discover.Scan(root, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(projects) != 1 || projects[0].Path != root {
+		t.Errorf("Expected only the root to be found, but got %+v", projects)
+	}
+}
+
+func TestScanMissingRoot(t *testing.T) {
+	_, err := discover.Scan(filepath.Join(t.TempDir(), "nope"), 2)
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+}
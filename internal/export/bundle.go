@@ -0,0 +1,128 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// BundleVersion is the version of the bundle format written by NewBundle.
+const BundleVersion = 1
+
+// RootPlaceholder stands for the projects directory in bundle paths.
+const RootPlaceholder = "$ROOT"
+
+// Bundle is a portable set of aliases that can be imported on another
+// machine. Paths under the home directory are written as `~/...`, paths under
+// the projects root (if given) as `$ROOT/...`, and stripped paths are empty.
+// Paths of remote aliases are kept as they are.
+type Bundle struct {
+	Version int               `json:"version"`
+	Aliases []config.DirAlias `json:"aliases"`
+}
+
+// NewBundle creates a bundle from the aliases of cfg, replacing the
+// machine-specific parts of their paths as described by opts.
+func NewBundle(cfg config.C, opts Options) (Bundle, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	root := ""
+	if opts.Root != "" {
+		root, err = filepath.Abs(opts.Root)
+		if err != nil {
+			return Bundle{}, err
+		}
+	}
+
+	bundle := Bundle{Version: BundleVersion, Aliases: []config.DirAlias{}}
+	for _, dirAlias := range cfg.DirAliases {
+		switch {
+		case dirAlias.IsRemote():
+			// Remote paths are the same on every machine
+		case opts.Strip:
+			dirAlias.Path = ""
+		case root != "" && isUnder(dirAlias.Path, root):
+			dirAlias.Path = templatize(dirAlias.Path, root, RootPlaceholder)
+		case isUnder(dirAlias.Path, home):
+			dirAlias.Path = templatize(dirAlias.Path, home, "~")
+		}
+
+		bundle.Aliases = append(bundle.Aliases, dirAlias)
+	}
+
+	return bundle, nil
+}
+
+// ReadBundle parses a bundle and resolves its paths for this machine. `~` is
+// expanded to the home directory and `$ROOT` to root. Stripped paths become
+// root/<alias>. An error is returned if a path needs root but it's empty.
+func ReadBundle(data []byte, root string) ([]config.DirAlias, error) {
+	var bundle Bundle
+	err := json.Unmarshal(data, &bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %v", bundle.Version)
+	}
+
+	if root != "" {
+		root, err = filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, dirAlias := range bundle.Aliases {
+		if dirAlias.IsRemote() {
+			continue
+		}
+
+		path := dirAlias.Path
+		needsRoot := path == "" || path == RootPlaceholder || strings.HasPrefix(path, RootPlaceholder+"/")
+		if needsRoot && root == "" {
+			return nil, fmt.Errorf("alias `%v` needs a projects root to be given", dirAlias.Alias)
+		}
+
+		switch {
+		case path == "":
+			path = filepath.Join(root, dirAlias.Alias)
+		case needsRoot:
+			path = filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(path, RootPlaceholder)))
+		default:
+			path, err = config.ExpandHome(path)
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Clean(path)
+		}
+
+		bundle.Aliases[i].Path = path
+	}
+
+	return bundle.Aliases, nil
+}
+
+func isUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// templatize replaces the dir prefix of path with placeholder and uses
+// forward slashes so that bundles work across operating systems.
+func templatize(path, dir, placeholder string) string {
+	rel, _ := filepath.Rel(dir, path)
+	if rel == "." {
+		return placeholder
+	}
+
+	return placeholder + "/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,204 @@
+// Package export renders Gopen aliases in formats meant for other tools and
+// other machines.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// Formats are the supported export formats.
+var Formats = []string{"sh", "fish", "csv", "json", "markdown", "bundle"}
+
+// Options tweak how aliases are rendered. They are only used by the bundle
+// format.
+type Options struct {
+	// Root is a projects directory to templatize in bundles
+	Root string
+	// Strip removes paths from bundles altogether
+	Strip bool
+}
+
+// Render writes the aliases of cfg to w in format.
+func Render(w io.Writer, cfg config.C, format string, opts Options) error {
+	switch format {
+	case "sh":
+		return renderShell(w, cfg, shQuote, "alias %v=%v\n")
+	case "fish":
+		return renderShell(w, cfg, fishQuote, "alias %v %v\n")
+	case "csv":
+		return renderCSV(w, cfg)
+	case "json":
+		return renderJSON(w, cfg.DirAliases)
+	case "markdown":
+		return renderMarkdown(w, cfg)
+	case "bundle":
+		bundle, err := NewBundle(cfg, opts)
+		if err != nil {
+			return err
+		}
+		return renderJSON(w, bundle)
+	}
+
+	return fmt.Errorf("unknown format `%v` (expected one of %v)", format, strings.Join(Formats, ", "))
+}
+
+// renderShell writes an alias definition per alias that does what
+// `gopen alias` does: cd into the path and run the editor command. File
+// aliases cd into the file's directory instead, and remote and container
+// aliases run the same commands as `gopen` does.
+func renderShell(w io.Writer, cfg config.C, quote func(string) string, aliasFmt string) error {
+	_, err := fmt.Fprintln(w, "# Generated by gopen export")
+	if err != nil {
+		return err
+	}
+
+	for _, dirAlias := range cfg.DirAliases {
+		editorLine, editorArgv := exportedEditor(cfg, dirAlias.Alias)
+
+		isFile := dirAlias.Kind == config.KindFile
+		dir := dirAlias.Path
+		if isFile {
+			dir = filepath.Dir(dirAlias.Path)
+		}
+
+		cmd := "cd " + quote(dir)
+		if editorLine != "" {
+			cmd += " && " + editorLine
+			if !cfg.CustomBehaviour || isFile {
+				cmd += " " + quote(dirAlias.Path)
+			}
+		}
+
+		var cmds []*exec.Cmd
+		switch {
+		case dirAlias.IsRemote():
+			cmds = []*exec.Cmd{dirAlias.RemoteCommand(editorArgv, nil, cfg.CustomBehaviour)}
+		case dirAlias.Container != "":
+			cmds, err = dirAlias.ContainerCommands(editorArgv, nil, cfg.CustomBehaviour)
+			if err != nil {
+				return err
+			}
+		}
+
+		if cmds != nil {
+			cmd = ""
+			for i, c := range cmds {
+				if i > 0 {
+					cmd += " && "
+				}
+				cmd += quoteArgs(c.Args, quote)
+			}
+		}
+
+		_, err = fmt.Fprintf(w, aliasFmt, dirAlias.Alias, quote(cmd))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportedEditor returns the editor command line and arguments that open
+// alias. EditorCmd is kept as written, and the editors that gopen falls back
+// to (like $EDITOR) are left out since they depend on the environment that
+// gopen runs in.
+func exportedEditor(cfg config.C, alias string) (string, []string) {
+	editor, source, err := cfg.ResolveEditor(alias, "")
+	if err != nil || !slices.Contains([]string{config.EditorFromAlias, config.EditorFromRule, config.EditorFromConfig}, source) {
+		return "", nil
+	}
+
+	if source == config.EditorFromConfig && cfg.DefaultEditor == "" {
+		return cfg.EditorCmd, editor.Argv()
+	}
+	return editor.CommandLine(), editor.Argv()
+}
+
+func quoteArgs(args []string, quote func(string) string) string {
+	var quoted []string
+	for _, arg := range args {
+		quoted = append(quoted, quote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+func renderCSV(w io.Writer, cfg config.C) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"alias", "path", "git_repo", "git_branch", "tags"})
+	if err != nil {
+		return err
+	}
+
+	for _, dirAlias := range cfg.DirAliases {
+		err = cw.Write([]string{
+			dirAlias.Alias,
+			dirAlias.Path,
+			dirAlias.GitRepo,
+			dirAlias.Branch,
+			strings.Join(dirAlias.Tags, ";"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func renderJSON(w io.Writer, v any) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// renderMarkdown writes a README-style index of the projects.
+func renderMarkdown(w io.Writer, cfg config.C) error {
+	var b strings.Builder
+	b.WriteString("# Projects\n\n")
+	b.WriteString("| Alias | Path | Repository | Tags |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+
+	for _, dirAlias := range cfg.DirAliases {
+		repo := ""
+		if dirAlias.GitRepo != "" {
+			repo = "`" + mdEscape(dirAlias.GitRepo) + "`"
+		}
+
+		fmt.Fprintf(&b, "| `%v` | `%v` | %v | %v |\n",
+			mdEscape(dirAlias.Alias),
+			mdEscape(dirAlias.Path),
+			repo,
+			mdEscape(strings.Join(dirAlias.Tags, ", ")),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mdEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,174 @@
+package export_test
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/export"
+)
+
+var testConfig = config.C{
+	EditorCmd: "vim",
+	DirAliases: []config.DirAlias{
+		{Alias: "docs", Path: "/usr/share/doc"},
+		{Alias: "quote", Path: "/code/it's here", GitRepo: "git@example.com:q.git", Tags: []string{"a", "b"}},
+	},
+}
+
+func render(t *testing.T, cfg config.C, format string, opts export.Options) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := export.Render(&buf, cfg, format, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestRenderFormats(t *testing.T) {
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{"sh", `# Generated by gopen export
+alias docs='cd '\''/usr/share/doc'\'' && vim '\''/usr/share/doc'\'''
+alias quote='cd '\''/code/it'\''\'\'''\''s here'\'' && vim '\''/code/it'\''\'\'''\''s here'\'''
+`},
+		{"fish", `# Generated by gopen export
+alias docs 'cd \'/usr/share/doc\' && vim \'/usr/share/doc\''
+alias quote 'cd \'/code/it\\\'s here\' && vim \'/code/it\\\'s here\''
+`},
+		{"csv", `alias,path,git_repo,git_branch,tags
+docs,/usr/share/doc,,,
+quote,/code/it's here,git@example.com:q.git,,a;b
+`},
+		{"markdown", "# Projects\n\n" +
+			"| Alias | Path | Repository | Tags |\n" +
+			"| --- | --- | --- | --- |\n" +
+			"| `docs` | `/usr/share/doc` |  |  |\n" +
+			"| `quote` | `/code/it's here` | `git@example.com:q.git` | a, b |\n"},
+	}
+
+	for _, c := range cases {
+		actual := render(t, testConfig, c.format, export.Options{})
+		if actual != c.expected {
+			t.Errorf("%v: Expected\n%v\nbut got\n%v", c.format, c.expected, actual)
+		}
+	}
+}
+
+func TestRenderShellRunsInShell(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+
+	cfg := config.C{
+		EditorCmd:  "echo",
+		DirAliases: []config.DirAlias{{Alias: "tmp", Path: t.TempDir() + "/it's"}},
+	}
+	script := render(t, cfg, "sh", export.Options{})
+
+	// Printing the alias back proves the quoting survives the shell
+	out, err := exec.Command(sh, "-c", script+"alias tmp").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "it") || !strings.Contains(string(out), "echo") {
+		t.Errorf("Expected the alias to be defined, but got %q", out)
+	}
+}
+
+func TestRenderShellFileAlias(t *testing.T) {
+	cfg := config.C{
+		EditorCmd:       "vim",
+		CustomBehaviour: true,
+		DirAliases: []config.DirAlias{
+			{Alias: "todo", Path: "/notes/todo.md", Kind: config.KindFile},
+		},
+	}
+
+	expected := `# Generated by gopen export
+alias todo='cd '\''/notes'\'' && vim '\''/notes/todo.md'\'''
+`
+	actual := render(t, cfg, "sh", export.Options{})
+	if actual != expected {
+		t.Errorf("Expected\n%v\nbut got\n%v", expected, actual)
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	err := export.Render(&bytes.Buffer{}, testConfig, "xml", export.Options{})
+	if err == nil {
+		t.Error("Expected an error, but got nil")
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	root := filepath.Join(home, "code")
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "proj", Path: filepath.Join(root, "proj"), GitRepo: "git@example.com:proj.git"},
+			{Alias: "notes", Path: filepath.Join(home, "notes")},
+			{Alias: "sys", Path: "/etc"},
+		},
+	}
+
+	bundle, err := export.NewBundle(cfg, export.Options{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, dirAlias := range bundle.Aliases {
+		paths = append(paths, dirAlias.Path)
+	}
+	expectedPaths := []string{"$ROOT/proj", "~/notes", "/etc"}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("Expected paths %v, but got %v", expectedPaths, paths)
+	}
+
+	data := render(t, cfg, "bundle", export.Options{Root: root})
+	newRoot := filepath.Join(home, "src")
+	aliases, err := export.ReadBundle([]byte(data), newRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []config.DirAlias{
+		{Alias: "proj", Path: filepath.Join(newRoot, "proj"), GitRepo: "git@example.com:proj.git"},
+		{Alias: "notes", Path: filepath.Join(home, "notes")},
+		{Alias: "sys", Path: "/etc"},
+	}
+	if !reflect.DeepEqual(aliases, expected) {
+		t.Errorf("Expected %v, but got %v", expected, aliases)
+	}
+
+	_, err = export.ReadBundle([]byte(data), "")
+	if err == nil {
+		t.Error("Expected an error for a bundle needing a root, but got nil")
+	}
+}
+
+func TestBundleStrip(t *testing.T) {
+	data := render(t, testConfig, "bundle", export.Options{Strip: true})
+
+	aliases, err := export.ReadBundle([]byte(data), "/src")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aliases[0].Path != filepath.Join("/src", "docs") || aliases[1].GitRepo != "git@example.com:q.git" {
+		t.Errorf("Expected stripped paths under the root with metadata kept, but got %v", aliases)
+	}
+}
@@ -0,0 +1,263 @@
+// Package importer reads the project lists of other directory-jumping and
+// editor tools and turns them into Gopen aliases.
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/export"
+)
+
+// Sources are the supported tools to import from.
+var Sources = []string{"autojump", "fasd", "projectile", "vscode", "zoxide"}
+
+// Entry is a path read from another tool's data together with its score in
+// that tool. Higher scores mean more frequently or recently used.
+//
+// Sources that know more about a project (like Gopen bundles) also set Alias,
+// the preferred alias, and Meta, whose options (everything but the alias and
+// path) are carried over to the imported alias.
+type Entry struct {
+	Path  string
+	Score float64
+	Alias string
+	Meta  config.DirAlias
+}
+
+// Parse reads data in the format of the tool named from and returns its
+// entries sorted by score, highest first.
+//
+// The expected data for each tool is:
+//
+//	autojump    autojump.txt ("weight<TAB>path" lines)
+//	fasd        ~/.fasd ("path|rank|time" lines)
+//	projectile  projectile-bookmarks.eld (a list of quoted paths)
+//	vscode      storage.json, or the JSON value of the
+//	            `history.recentlyOpenedPathsList` key in state.vscdb
+//	zoxide      db.zo, or the output of `zoxide query --list --score`
+func Parse(from string, data []byte) ([]Entry, error) {
+	var entries []Entry
+	var err error
+
+	switch from {
+	case "autojump":
+		entries, err = parseLines(data, "\t", 1, 0)
+	case "fasd":
+		entries, err = parseLines(data, "|", 0, 1)
+	case "projectile":
+		entries, err = parseProjectile(data)
+	case "vscode":
+		entries, err = parseVSCode(data)
+	case "zoxide":
+		entries, err = parseZoxide(data)
+	default:
+		return nil, fmt.Errorf("unknown source `%v` (expected one of %v)", from, strings.Join(Sources, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %v data: %v", from, err)
+	}
+
+	for i, e := range entries {
+		path, err := config.ExpandHome(e.Path)
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Path = filepath.Clean(path)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Score > entries[j].Score
+	})
+
+	return entries, nil
+}
+
+// ParseBundle reads a bundle written by `gopen export --format bundle`,
+// resolving its paths with root as described in export.ReadBundle. Entries
+// keep the order and metadata of the bundle.
+func ParseBundle(data []byte, root string) ([]Entry, error) {
+	aliases, err := export.ReadBundle(data, root)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse bundle: %v", err)
+	}
+
+	var entries []Entry
+	for _, dirAlias := range aliases {
+		entries = append(entries, Entry{Path: dirAlias.Path, Alias: dirAlias.Alias, Meta: dirAlias})
+	}
+
+	return entries, nil
+}
+
+// parseLines parses text with one entry per line, where fields are separated
+// by sep. pathField and scoreField are the indexes of the path and the score.
+func parseLines(data []byte, sep string, pathField, scoreField int) ([]Entry, error) {
+	var entries []Entry
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Split(line, sep)
+		if len(fields) <= max(pathField, scoreField) {
+			return nil, fmt.Errorf("line %d: expected at least %d fields", i+1, max(pathField, scoreField)+1)
+		}
+
+		score, err := strconv.ParseFloat(strings.TrimSpace(fields[scoreField]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid score: %v", i+1, err)
+		}
+
+		entries = append(entries, Entry{Path: fields[pathField], Score: score})
+	}
+
+	return entries, nil
+}
+
+var quotedString = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+// parseProjectile reads the elisp list of quoted paths in a projectile
+// bookmarks file. Paths are scored by their position so that the list order
+// is kept.
+func parseProjectile(data []byte) ([]Entry, error) {
+	var entries []Entry
+	matches := quotedString.FindAllSubmatch(data, -1)
+	for i, m := range matches {
+		path := strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(string(m[1]))
+		entries = append(entries, Entry{Path: path, Score: float64(len(matches) - i)})
+	}
+
+	return entries, nil
+}
+
+type vscodeRecents struct {
+	Entries []struct {
+		FolderURI string `json:"folderUri"`
+	} `json:"entries"`
+}
+
+// parseVSCode reads the recently opened folders of VS Code. Only local
+// folders are imported, ranked by how recently they were opened.
+func parseVSCode(data []byte) ([]Entry, error) {
+	var storage struct {
+		OpenedPathsList *vscodeRecents `json:"openedPathsList"`
+		vscodeRecents
+	}
+
+	err := json.Unmarshal(data, &storage)
+	if err != nil {
+		return nil, err
+	}
+
+	recents := storage.vscodeRecents
+	if storage.OpenedPathsList != nil {
+		recents = *storage.OpenedPathsList
+	}
+
+	var paths []string
+	for _, e := range recents.Entries {
+		u, err := url.Parse(e.FolderURI)
+		if err != nil || u.Scheme != "file" {
+			continue
+		}
+		paths = append(paths, filepath.FromSlash(u.Path))
+	}
+
+	var entries []Entry
+	for i, path := range paths {
+		entries = append(entries, Entry{Path: path, Score: float64(len(paths) - i)})
+	}
+
+	return entries, nil
+}
+
+// zoxideVersion is the version of the zoxide database format that can be
+// read.
+const zoxideVersion = 3
+
+// parseZoxide reads either a zoxide database, or the text output of
+// `zoxide query --list --score` ("score path" lines).
+func parseZoxide(data []byte) ([]Entry, error) {
+	if len(data) < 4 || binary.LittleEndian.Uint32(data) != zoxideVersion {
+		return parseZoxideText(data)
+	}
+
+	// The database is bincode-encoded: a u32 version followed by a u64
+	// count of entries, each being a u64-length-prefixed path, an f64
+	// rank, and a u64 last access time
+	r := bytes.NewReader(data[4:])
+	var count uint64
+	err := binary.Read(r, binary.LittleEndian, &count)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for i := uint64(0); i < count; i++ {
+		var pathLen uint64
+		err = binary.Read(r, binary.LittleEndian, &pathLen)
+		if err != nil {
+			return nil, err
+		}
+		if pathLen > uint64(r.Len()) {
+			return nil, errors.New("truncated database")
+		}
+
+		path := make([]byte, pathLen)
+		_, err = r.Read(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var rank, lastAccessed uint64
+		err = binary.Read(r, binary.LittleEndian, &rank)
+		if err != nil {
+			return nil, err
+		}
+		err = binary.Read(r, binary.LittleEndian, &lastAccessed)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, Entry{Path: string(path), Score: math.Float64frombits(rank)})
+	}
+
+	return entries, nil
+}
+
+func parseZoxideText(data []byte) ([]Entry, error) {
+	var entries []Entry
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		scoreStr, path, found := strings.Cut(line, " ")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected a score and a path", i+1)
+		}
+
+		score, err := strconv.ParseFloat(scoreStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid score: %v", i+1, err)
+		}
+
+		entries = append(entries, Entry{Path: strings.TrimSpace(path), Score: score})
+	}
+
+	return entries, nil
+}
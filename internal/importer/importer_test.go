@@ -0,0 +1,140 @@
+package importer_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/importer"
+)
+
+func TestParseTextFormats(t *testing.T) {
+	cases := []struct {
+		from     string
+		data     string
+		expected []importer.Entry
+	}{
+		{
+			from: "autojump",
+			data: "10.0\t/code/low\n42.5\t/code/high\n",
+			expected: []importer.Entry{
+				{Path: "/code/high", Score: 42.5},
+				{Path: "/code/low", Score: 10},
+			},
+		},
+		{
+			from: "fasd",
+			data: "/code/a|3|1700000000\n/code/b/|7|1700000001\n",
+			expected: []importer.Entry{
+				{Path: "/code/b", Score: 7},
+				{Path: "/code/a", Score: 3},
+			},
+		},
+		{
+			from: "zoxide",
+			data: "  12.0 /code/z one\n   4.0 /code/y\n",
+			expected: []importer.Entry{
+				{Path: "/code/z one", Score: 12},
+				{Path: "/code/y", Score: 4},
+			},
+		},
+		{
+			from: "projectile",
+			data: `("/code/first/" "/code/second/" "/code/with \"quote\"/")`,
+			expected: []importer.Entry{
+				{Path: "/code/first", Score: 3},
+				{Path: "/code/second", Score: 2},
+				{Path: `/code/with "quote"`, Score: 1},
+			},
+		},
+		{
+			from: "vscode",
+			data: `{"openedPathsList": {"entries": [
+				{"folderUri": "file:///code/recent"},
+				{"fileUri": "file:///code/notes.md"},
+				{"folderUri": "vscode-remote://ssh-remote%2Bvm/code/remote"},
+				{"folderUri": "file:///code/with%20space"}
+			]}}`,
+			expected: []importer.Entry{
+				{Path: "/code/recent", Score: 2},
+				{Path: "/code/with space", Score: 1},
+			},
+		},
+		{
+			from: "vscode",
+			data: `{"entries": [{"folderUri": "file:///code/state"}]}`,
+			expected: []importer.Entry{
+				{Path: "/code/state", Score: 1},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		entries, err := importer.Parse(c.from, []byte(c.data))
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.from, err)
+			continue
+		}
+		if !reflect.DeepEqual(entries, c.expected) {
+			t.Errorf("%v: Expected %v, but got %v", c.from, c.expected, entries)
+		}
+	}
+}
+
+func TestParseZoxideDatabase(t *testing.T) {
+	var buf bytes.Buffer
+	write := func(v any) {
+		err := binary.Write(&buf, binary.LittleEndian, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dirs := []struct {
+		path string
+		rank float64
+	}{
+		{"/code/low", 1.5},
+		{"/code/high", 20},
+	}
+
+	write(uint32(3))
+	write(uint64(len(dirs)))
+	for _, d := range dirs {
+		write(uint64(len(d.path)))
+		buf.WriteString(d.path)
+		write(d.rank)
+		write(uint64(1700000000))
+	}
+
+	entries, err := importer.Parse("zoxide", buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []importer.Entry{
+		{Path: "/code/high", Score: 20},
+		{Path: "/code/low", Score: 1.5},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %v, but got %v", expected, entries)
+	}
+
+	_, err = importer.Parse("zoxide", buf.Bytes()[:20])
+	if err == nil {
+		t.Error("Expected an error for a truncated database, but got nil")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := importer.Parse("nope", nil)
+	if err == nil {
+		t.Error("Expected an error for an unknown source, but got nil")
+	}
+
+	_, err = importer.Parse("autojump", []byte("not-a-number\t/code\n"))
+	if err == nil {
+		t.Error("Expected an error for an invalid score, but got nil")
+	}
+}
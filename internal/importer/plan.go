@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// Policies for entries whose suggested alias already exists in the config.
+const (
+	OnConflictSkip      = "skip"
+	OnConflictOverwrite = "overwrite"
+	OnConflictRename    = "rename"
+)
+
+// Operations an Action can have.
+const (
+	OpAdd       = "add"
+	OpOverwrite = "overwrite"
+	OpRename    = "rename"
+	OpSkip      = "skip"
+)
+
+// Action is what importing a single entry will do. Reason explains skipped
+// and renamed entries.
+type Action struct {
+	Op     string
+	Alias  string
+	Path   string
+	Reason string
+	Meta   config.DirAlias
+}
+
+// Plan decides what to do with each entry without changing cfg. Entries
+// whose path is already aliased, or doesn't exist and has no git repo to be
+// cloned from, are skipped. Entries whose suggested alias exists in cfg are
+// handled according to onConflict, while reserved aliases and clashes
+// between imported entries are always resolved by renaming.
+func Plan(cfg config.C, entries []Entry, onConflict string) ([]Action, error) {
+	if !slices.Contains([]string{OnConflictSkip, OnConflictOverwrite, OnConflictRename}, onConflict) {
+		return nil, fmt.Errorf("invalid conflict policy `%v` (expected skip, overwrite, or rename)", onConflict)
+	}
+
+	existing := map[string]bool{}
+	aliasedPaths := map[string]string{}
+	var taken []string
+	for _, dirAlias := range cfg.DirAliases {
+		existing[dirAlias.Alias] = true
+		aliasedPaths[dirAlias.Location()] = dirAlias.Alias
+		taken = append(taken, dirAlias.Alias)
+	}
+
+	var actions []Action
+	imported := map[string]bool{}
+	for _, e := range entries {
+		a := Action{Op: OpAdd, Path: e.Path, Alias: e.Alias, Meta: e.Meta}
+		if a.Alias == "" {
+			a.Alias = config.SuggestAlias(e.Path, nil)
+		}
+
+		// Remote paths can't be checked locally
+		var info os.FileInfo
+		var err error
+		if !e.Meta.IsRemote() {
+			info, err = os.Stat(e.Path)
+		}
+
+		location := e.Path
+		if e.Meta.IsRemote() {
+			location = e.Meta.Host + ":" + e.Path
+		}
+
+		switch {
+		case e.Meta.IsRemote():
+			a.Reason = "on " + e.Meta.Host
+		case err != nil && e.Meta.GitRepo != "":
+			a.Reason = "will be cloned by `gopen sync`"
+		case err != nil:
+			a.Op, a.Reason = OpSkip, "path doesn't exist"
+		case !info.IsDir() && e.Meta.Kind != config.KindFile:
+			a.Op, a.Reason = OpSkip, "not a directory"
+		}
+
+		// Entries that will be cloned are still checked for conflicts
+		switch {
+		case a.Op == OpSkip:
+		case aliasedPaths[location] != "":
+			a.Op, a.Reason = OpSkip, fmt.Sprintf("already aliased as `%v`", aliasedPaths[location])
+		case config.IsReserved(a.Alias):
+			a.Op, a.Reason = OpRename, fmt.Sprintf("`%v` is reserved", a.Alias)
+			a.Alias = renamed(e, taken)
+		case imported[a.Alias]:
+			a.Op, a.Reason = OpRename, fmt.Sprintf("`%v` is imported from another path", a.Alias)
+			a.Alias = renamed(e, taken)
+		case existing[a.Alias] && onConflict == OnConflictSkip:
+			a.Op, a.Reason = OpSkip, fmt.Sprintf("`%v` already exists", a.Alias)
+		case existing[a.Alias] && onConflict == OnConflictOverwrite:
+			a.Op = OpOverwrite
+		case existing[a.Alias]:
+			a.Op, a.Reason = OpRename, fmt.Sprintf("`%v` already exists", a.Alias)
+			a.Alias = renamed(e, taken)
+		}
+
+		if a.Op != OpSkip {
+			imported[a.Alias] = true
+			aliasedPaths[location] = a.Alias
+			taken = append(taken, a.Alias)
+		}
+		actions = append(actions, a)
+	}
+
+	return actions, nil
+}
+
+// renamed returns a free alias for e, based on its own alias if it has one
+// (like bundle entries) and on its path otherwise.
+func renamed(e Entry, taken []string) string {
+	if e.Alias != "" {
+		return config.UniqueAlias(e.Alias, taken)
+	}
+	return config.SuggestAlias(e.Path, taken)
+}
+
+// Apply adds the aliases of all actions that aren't skipped to cfg. Entries
+// from bundles also bring their metadata: git repo and clone options, tags,
+// container and multiplexer options, detach mode, and editor.
+func Apply(cfg config.C, actions []Action) (config.C, error) {
+	var err error
+	for _, a := range actions {
+		if a.Op == OpSkip {
+			continue
+		}
+
+		if a.Meta.IsRemote() {
+			cfg, err = cfg.AddRemoteAlias(a.Alias, a.Meta.Host, a.Path)
+		} else {
+			cfg, err = cfg.AddAlias(a.Alias, a.Path)
+		}
+		if err != nil {
+			return cfg, err
+		}
+
+		// Only bundles carry metadata, with Meta set to the exported alias.
+		// Other entries keep what AddAlias kept or detected, like the git
+		// repo from the `origin` remote or the options of an overwritten
+		// alias.
+		if a.Meta.Alias == "" {
+			continue
+		}
+
+		cfg, err = applyMeta(cfg, a.Alias, a.Meta)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// applyMeta sets the options in meta on alias. The validating setters go
+// first so that a broken bundle can't sneak invalid options in, then the
+// rest is carried over as is. A git repo detected by AddAlias is kept if the
+// bundle has none.
+func applyMeta(cfg config.C, alias string, meta config.DirAlias) (config.C, error) {
+	cfg, err := cfg.SetGitOptions(alias, meta.GitOptions)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg, err = cfg.SetContainerOptions(alias, meta.ContainerOptions)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg, err = cfg.SetMuxOptions(alias, meta.MuxOptions)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg, err = cfg.SetAliasDetach(alias, meta.Detach)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg, err = cfg.SetTags(alias, meta.Tags)
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg.UpdateAlias(alias, func(d *config.DirAlias) error {
+		if meta.GitRepo != "" {
+			d.GitRepo = meta.GitRepo
+		}
+		d.Editor = meta.Editor
+		if d.Kind == "" {
+			d.Kind = meta.Kind
+		}
+		return nil
+	})
+}
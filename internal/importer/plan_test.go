@@ -0,0 +1,213 @@
+package importer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/importer"
+)
+
+func TestPlan(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api", "web", "other/api", "aliased"} {
+		err := os.MkdirAll(filepath.Join(root, dir), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := filepath.Join(root, "notes.txt")
+	err := os.WriteFile(file, nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.C{
+		DirAliases: []config.DirAlias{
+			{Alias: "web", Path: "/elsewhere/web"},
+			{Alias: "mine", Path: filepath.Join(root, "aliased")},
+		},
+	}
+	entries := []importer.Entry{
+		{Path: filepath.Join(root, "api")},
+		{Path: filepath.Join(root, "web")},
+		{Path: filepath.Join(root, "other/api")},
+		{Path: filepath.Join(root, "aliased")},
+		{Path: filepath.Join(root, "gone")},
+		{Path: file},
+	}
+
+	cases := []struct {
+		policy   string
+		expected []string
+	}{
+		{importer.OnConflictSkip, []string{"add api", "skip web", "rename api-2", "skip aliased", "skip gone", "skip notes.txt"}},
+		{importer.OnConflictRename, []string{"add api", "rename web-2", "rename api-2", "skip aliased", "skip gone", "skip notes.txt"}},
+		{importer.OnConflictOverwrite, []string{"add api", "overwrite web", "rename api-2", "skip aliased", "skip gone", "skip notes.txt"}},
+	}
+
+	for _, c := range cases {
+		policy, expected := c.policy, c.expected
+		actions, err := importer.Plan(cfg, entries, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, a := range actions {
+			if a.Op+" "+a.Alias != expected[i] {
+				t.Errorf("%v: Expected %q at %d, but got %q", policy, expected[i], i, a.Op+" "+a.Alias)
+			}
+		}
+
+	}
+
+	actions, err := importer.Plan(cfg, entries, importer.OnConflictRename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCfg, err := importer.Apply(cfg, actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(newCfg.DirAliases) != 5 {
+		t.Errorf("Expected 3 aliases to be added, but got %v", newCfg.DirAliases)
+	}
+
+	_, err = importer.Plan(cfg, entries, "merge")
+	if err == nil {
+		t.Error("Expected an error for an invalid policy, but got nil")
+	}
+}
+
+func TestPlanBundleKeepsMetadata(t *testing.T) {
+	root := t.TempDir()
+	bundle := `{"version": 1, "aliases": [
+		{"alias": "proj", "path": "$ROOT/proj", "git_repo": "git@example.com:proj.git", "git_branch": "dev", "tags": ["work"],
+		 "detach": "always", "editor": "code", "container": "docker:web", "mux_windows": ["make watch"]},
+		{"alias": "local", "path": "$ROOT/local"}
+	]}`
+
+	entries, err := importer.ParseBundle([]byte(bundle), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions, err := importer.Plan(config.C{}, entries, importer.OnConflictSkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actions[0].Op != importer.OpAdd || actions[1].Op != importer.OpSkip {
+		t.Errorf("Expected the clonable alias to be added and the other skipped, but got %+v", actions)
+	}
+
+	cfg, err := importer.Apply(config.C{}, actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := cfg.DirAliases[0]
+	if got.Path != filepath.Join(root, "proj") || got.GitRepo != "git@example.com:proj.git" ||
+		got.Branch != "dev" || len(got.Tags) != 1 || got.Detach != "always" || got.Editor != "code" ||
+		got.Container != "docker:web" || len(got.Windows) != 1 {
+		t.Errorf("Expected the bundle metadata to be kept, but got %+v", got)
+	}
+}
+
+func TestPlanSkipsClonableConflicts(t *testing.T) {
+	root := t.TempDir()
+	entries := []importer.Entry{{
+		Alias: "proj",
+		Path:  filepath.Join(root, "proj"),
+		Meta:  config.DirAlias{GitRepo: "git@example.com:proj.git"},
+	}}
+	cfg := config.C{DirAliases: []config.DirAlias{{Alias: "proj", Path: root}}}
+
+	actions, err := importer.Plan(cfg, entries, importer.OnConflictSkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actions[0].Op != importer.OpSkip {
+		t.Errorf("Expected the conflicting clonable alias to be skipped, but got %+v", actions[0])
+	}
+}
+
+func TestApplyKeepsDetectedAndExistingOptions(t *testing.T) {
+	root := t.TempDir()
+	repo, err := git.PlainInit(root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{"git@example.com:proj.git"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := importer.Parse("zoxide", []byte("10 "+root+"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions, err := importer.Plan(config.C{}, entries, importer.OnConflictSkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := importer.Apply(config.C{}, actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DirAliases[0].GitRepo != "git@example.com:proj.git" {
+		t.Errorf("Expected the origin remote to be kept, but got %+v", cfg.DirAliases[0])
+	}
+
+	// Overwriting an alias from a source without metadata keeps its options
+	existing := config.C{DirAliases: []config.DirAlias{
+		{Alias: "proj", Path: t.TempDir(), Tags: []string{"work"}, Detach: config.DetachNever},
+	}}
+	actions, err = importer.Plan(existing, []importer.Entry{{Path: root, Alias: "proj"}}, importer.OnConflictOverwrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = importer.Apply(existing, actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := cfg.DirAliases[0]
+	if got.Path != root || len(got.Tags) != 1 || got.Detach != config.DetachNever {
+		t.Errorf("Expected the options of the overwritten alias to be kept, but got %+v", got)
+	}
+}
+
+func TestPlanRenamesReservedAndBundleAliases(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "b"} {
+		err := os.Mkdir(filepath.Join(root, dir), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := []importer.Entry{
+		{Alias: "sync", Path: filepath.Join(root, "a"), Meta: config.DirAlias{Alias: "sync"}},
+		{Alias: "shop", Path: filepath.Join(root, "b"), Meta: config.DirAlias{Alias: "shop"}},
+	}
+	cfg := config.C{DirAliases: []config.DirAlias{{Alias: "shop", Path: t.TempDir()}}}
+
+	actions, err := importer.Plan(cfg, entries, importer.OnConflictRename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Renames start from the alias of the entry, not from its path
+	expected := []string{"sync-2", "shop-2"}
+	for i, a := range actions {
+		if a.Op != importer.OpRename || a.Alias != expected[i] {
+			t.Errorf("Expected %v to be renamed to %v, but got %+v", entries[i].Alias, expected[i], a)
+		}
+	}
+
+	_, err = importer.Apply(cfg, actions)
+	if err != nil {
+		t.Errorf("Expected the renamed aliases to be applied, but got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// Outcomes of pulling a single project.
+const (
+	PullUpdated  = "updated"
+	PullUpToDate = "up to date"
+	PullSkipped  = "skipped"
+	PullFailed   = "failed"
+)
+
+// PullResult is the outcome of pulling the project behind a single alias.
+// Detail explains skipped and failed pulls.
+type PullResult struct {
+	Alias   string
+	Outcome string
+	Detail  string
+}
+
+// Pull fetches the upstream branch of the project behind dirAlias and
+// fast-forwards the current branch to it. Projects with uncommitted changes,
+// a detached HEAD, or a branch that diverged from its upstream are skipped, and
+// so are remote aliases.
+func Pull(dirAlias config.DirAlias) PullResult {
+	res := PullResult{Alias: dirAlias.Alias, Outcome: PullSkipped}
+
+	if dirAlias.IsRemote() {
+		res.Detail = "remote alias on " + dirAlias.Host
+		return res
+	}
+
+	_, err := os.Stat(dirAlias.Path)
+	if os.IsNotExist(err) {
+		res.Detail = "missing (clone it with `gopen sync`)"
+		return res
+	}
+
+	r, err := git.PlainOpen(dirAlias.Path)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		res.Detail = "not a git repo"
+		return res
+	}
+	if err != nil {
+		return failed(res, err)
+	}
+
+	head, err := r.Head()
+	if err != nil {
+		return failed(res, err)
+	}
+	if !head.Name().IsBranch() {
+		res.Detail = "detached HEAD"
+		return res
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return failed(res, err)
+	}
+
+	wStatus, err := w.Status()
+	if err != nil {
+		return failed(res, err)
+	}
+	if !wStatus.IsClean() {
+		res.Detail = "uncommitted changes"
+		return res
+	}
+
+	remote, _, err := tracking(r, head.Name().Short())
+	if err != nil {
+		return failed(res, err)
+	}
+
+	auth, err := config.AuthMethod(dirAlias)
+	if err != nil {
+		return failed(res, err)
+	}
+
+	err = r.Fetch(&git.FetchOptions{RemoteName: remote, Auth: auth})
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		res.Detail = "no remote `" + remote + "`"
+		return res
+	}
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return failed(res, err)
+	}
+
+	upstream, err := upstreamRef(r, head.Name().Short())
+	if err != nil {
+		return failed(res, err)
+	}
+	if upstream == nil {
+		res.Detail = "no upstream branch"
+		return res
+	}
+
+	ahead, behind, err := aheadBehind(r, head.Hash(), upstream.Hash())
+	if err != nil {
+		return failed(res, err)
+	}
+
+	switch {
+	case behind == 0:
+		res.Outcome = PullUpToDate
+		return res
+	case ahead > 0:
+		res.Detail = "diverged from " + upstream.Name().Short()
+		return res
+	}
+
+	// The worktree is clean, so resetting to the upstream commit is a
+	// fast-forward of both the branch and the files
+	err = w.Reset(&git.ResetOptions{Commit: upstream.Hash(), Mode: git.HardReset})
+	if err != nil {
+		return failed(res, err)
+	}
+
+	res.Outcome = PullUpdated
+	res.Detail = fmt.Sprintf("%d new commit(s) from %v", behind, upstream.Name().Short())
+	return res
+}
+
+func failed(res PullResult, err error) PullResult {
+	res.Outcome = PullFailed
+	res.Detail = err.Error()
+	return res
+}
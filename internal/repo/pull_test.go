@@ -0,0 +1,117 @@
+package repo_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/repo"
+)
+
+// cloneRepo clones the repo at src into a new directory and returns its path.
+func cloneRepo(t *testing.T, src string) string {
+	t.Helper()
+
+	// Cloning over the file transport shells out to git-upload-pack
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	dst := filepath.Join(t.TempDir(), "clone")
+	_, err := git.PlainClone(dst, false, &git.CloneOptions{URL: src})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dst
+}
+
+func commitFile(t *testing.T, dir, file string) {
+	t.Helper()
+
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, file), []byte(file), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Add(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Commit(file, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPullFastForwards(t *testing.T) {
+	_, src, _ := newRepo(t, 1)
+	dst := cloneRepo(t, src)
+	dirAlias := config.DirAlias{Alias: "proj", Path: dst}
+
+	res := repo.Pull(dirAlias)
+	if res.Outcome != repo.PullUpToDate {
+		t.Errorf("expected %q, but got %+v", repo.PullUpToDate, res)
+	}
+
+	commitFile(t, src, "new.txt")
+	res = repo.Pull(dirAlias)
+	if res.Outcome != repo.PullUpdated {
+		t.Fatalf("expected %q, but got %+v", repo.PullUpdated, res)
+	}
+
+	_, err := os.Stat(filepath.Join(dst, "new.txt"))
+	if err != nil {
+		t.Errorf("expected new.txt to be pulled: %v", err)
+	}
+}
+
+func TestPullSkipsDirtyAndDiverged(t *testing.T) {
+	_, src, _ := newRepo(t, 1)
+	dst := cloneRepo(t, src)
+	dirAlias := config.DirAlias{Alias: "proj", Path: dst}
+
+	commitFile(t, src, "upstream.txt")
+
+	err := os.WriteFile(filepath.Join(dst, "file.txt"), []byte("changed"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := repo.Pull(dirAlias)
+	if res.Outcome != repo.PullSkipped || res.Detail != "uncommitted changes" {
+		t.Errorf("expected a dirty tree to be skipped, but got %+v", res)
+	}
+
+	commitFile(t, dst, "file.txt")
+	res = repo.Pull(dirAlias)
+	if res.Outcome != repo.PullSkipped || res.Detail != "diverged from origin/master" {
+		t.Errorf("expected a diverged branch to be skipped, but got %+v", res)
+	}
+}
+
+func TestPullSkipsMissingAndNonRepos(t *testing.T) {
+	res := repo.Pull(config.DirAlias{Alias: "gone", Path: filepath.Join(t.TempDir(), "gone")})
+	if res.Outcome != repo.PullSkipped {
+		t.Errorf("expected a missing project to be skipped, but got %+v", res)
+	}
+
+	res = repo.Pull(config.DirAlias{Alias: "plain", Path: t.TempDir()})
+	if res.Outcome != repo.PullSkipped || res.Detail != "not a git repo" {
+		t.Errorf("expected a non-repo to be skipped, but got %+v", res)
+	}
+}
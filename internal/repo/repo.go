@@ -0,0 +1,54 @@
+// Package repo contains git operations that run across many Gopen projects at
+// once, such as cloning, inspecting, or updating them.
+package repo
+
+import (
+	"sync"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// DefaultJobs is the number of aliases processed concurrently when no other
+// limit is given.
+const DefaultJobs = 4
+
+// Each runs fn for every alias with at most jobs calls running at the same
+// time, and returns the results in the same order as aliases. If onDone isn't
+// nil, it is called with the index and result of each alias as soon as it
+// finishes. Calls to onDone never overlap.
+func Each[T any](
+	aliases []config.DirAlias,
+	jobs int,
+	fn func(config.DirAlias) T,
+	onDone func(int, T),
+) []T {
+	if jobs < 1 {
+		jobs = DefaultJobs
+	}
+
+	results := make([]T, len(aliases))
+	sem := make(chan struct{}, jobs)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, dirAlias := range aliases {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, dirAlias config.DirAlias) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := fn(dirAlias)
+			results[i] = result
+
+			if onDone != nil {
+				mu.Lock()
+				onDone(i, result)
+				mu.Unlock()
+			}
+		}(i, dirAlias)
+	}
+
+	wg.Wait()
+	return results
+}
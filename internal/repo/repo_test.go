@@ -0,0 +1,50 @@
+package repo_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/repo"
+)
+
+func TestEachKeepsOrderAndLimitsJobs(t *testing.T) {
+	aliases := []config.DirAlias{
+		{Alias: "a"}, {Alias: "b"}, {Alias: "c"}, {Alias: "d"}, {Alias: "e"},
+	}
+
+	var running, maxRunning int32
+	var doneCount int
+	results := repo.Each(aliases, 2, func(dirAlias config.DirAlias) string {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return dirAlias.Alias + "!"
+	}, func(i int, result string) {
+		doneCount++
+		if result != aliases[i].Alias+"!" {
+			t.Errorf("onDone got %q for alias %q", result, aliases[i].Alias)
+		}
+	})
+
+	for i, result := range results {
+		if result != aliases[i].Alias+"!" {
+			t.Errorf("expected %q at index %d, but got %q", aliases[i].Alias+"!", i, result)
+		}
+	}
+
+	if doneCount != len(aliases) {
+		t.Errorf("expected onDone to be called %d times, but got %d", len(aliases), doneCount)
+	}
+
+	if maxRunning > 2 {
+		t.Errorf("expected at most 2 concurrent jobs, but got %d", maxRunning)
+	}
+}
@@ -0,0 +1,191 @@
+package repo
+
+import (
+	"errors"
+	"os"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// Status describes the state of the project behind a single alias.
+type Status struct {
+	Alias    string `json:"alias"`
+	Path     string `json:"path"`
+	Host     string `json:"host,omitempty"`
+	Exists   bool   `json:"exists"`
+	IsRepo   bool   `json:"is_repo"`
+	Branch   string `json:"branch,omitempty"`
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Dirty    bool   `json:"dirty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// GetStatus inspects the path of dirAlias and reports whether it exists and,
+// if it's a git repo, its branch, its position relative to the upstream
+// branch, and whether the working tree has uncommitted changes. For remote
+// aliases, only whether the path exists on the host is reported.
+func GetStatus(dirAlias config.DirAlias) Status {
+	s := Status{Alias: dirAlias.Alias, Path: dirAlias.Path, Host: dirAlias.Host}
+
+	// Only the existence of remote paths is checked to keep SSH round trips
+	// to a minimum
+	if dirAlias.IsRemote() {
+		exists, err := dirAlias.RemoteExists(config.DefaultRunner)
+		if err != nil {
+			s.Error = err.Error()
+		}
+		s.Exists = exists
+		return s
+	}
+
+	_, err := os.Stat(dirAlias.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.Error = err.Error()
+		}
+		return s
+	}
+	s.Exists = true
+
+	r, err := git.PlainOpen(dirAlias.Path)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return s
+	}
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.IsRepo = true
+
+	err = fillStatus(r, &s)
+	if err != nil {
+		s.Error = err.Error()
+	}
+
+	return s
+}
+
+func fillStatus(r *git.Repository, s *Status) error {
+	head, err := r.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// Freshly initialized repo without any commits
+		s.Branch = "(no commits)"
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+
+	wStatus, err := w.Status()
+	if err != nil {
+		return err
+	}
+	s.Dirty = !wStatus.IsClean()
+
+	if !head.Name().IsBranch() {
+		s.Branch = "(detached at " + head.Hash().String()[:7] + ")"
+		return nil
+	}
+	s.Branch = head.Name().Short()
+
+	upstream, err := upstreamRef(r, s.Branch)
+	if err != nil || upstream == nil {
+		return err
+	}
+	s.Upstream = upstream.Name().Short()
+
+	s.Ahead, s.Behind, err = aheadBehind(r, head.Hash(), upstream.Hash())
+	return err
+}
+
+// upstreamRef returns the remote-tracking reference that branch follows, or
+// nil if it has none.
+func upstreamRef(r *git.Repository, branch string) (*plumbing.Reference, error) {
+	remote, remoteBranch, err := tracking(r, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := r.Reference(plumbing.NewRemoteReferenceName(remote, remoteBranch), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+
+	return ref, err
+}
+
+// tracking returns the remote and remote branch that branch follows.
+// Branches without tracking config fall back to origin/<branch>.
+func tracking(r *git.Repository, branch string) (string, string, error) {
+	cfg, err := r.Config()
+	if err != nil {
+		return "", "", err
+	}
+
+	if b, ok := cfg.Branches[branch]; ok && b.Remote != "" && b.Merge.IsBranch() {
+		return b.Remote, b.Merge.Short(), nil
+	}
+
+	return "origin", branch, nil
+}
+
+// aheadBehind counts the commits reachable only from local (ahead) and only
+// from upstream (behind).
+func aheadBehind(r *git.Repository, local, upstream plumbing.Hash) (int, int, error) {
+	if local == upstream {
+		return 0, 0, nil
+	}
+
+	localCommits, err := ancestors(r, local)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	upstreamCommits, err := ancestors(r, upstream)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ahead, behind := 0, 0
+	for h := range localCommits {
+		if _, ok := upstreamCommits[h]; !ok {
+			ahead++
+		}
+	}
+	for h := range upstreamCommits {
+		if _, ok := localCommits[h]; !ok {
+			behind++
+		}
+	}
+
+	return ahead, behind, nil
+}
+
+func ancestors(r *git.Repository, from plumbing.Hash) (map[plumbing.Hash]struct{}, error) {
+	commit, err := r.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[plumbing.Hash]struct{}{}
+	err = object.NewCommitPreorderIter(commit, nil, nil).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = struct{}{}
+		return nil
+	})
+	// Shallow clones are missing the history beyond their depth
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		err = nil
+	}
+
+	return seen, err
+}
@@ -0,0 +1,109 @@
+package repo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/repo"
+)
+
+// newRepo creates a repo with n commits on master and returns it together
+// with its path and the commit hashes in order.
+func newRepo(t *testing.T, n int) (*git.Repository, string, []plumbing.Hash) {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hashes []plumbing.Hash
+	for i := 0; i < n; i++ {
+		file := filepath.Join(dir, "file.txt")
+		err := os.WriteFile(file, []byte{byte('a' + i)}, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = w.Add("file.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := w.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, h)
+	}
+
+	return r, dir, hashes
+}
+
+func setRemoteRef(t *testing.T, r *git.Repository, h plumbing.Hash) {
+	t.Helper()
+
+	ref := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "master"), h)
+	err := r.Storer.SetReference(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetStatusMissing(t *testing.T) {
+	s := repo.GetStatus(config.DirAlias{Alias: "gone", Path: filepath.Join(t.TempDir(), "gone")})
+	if s.Exists || s.IsRepo || s.Error != "" {
+		t.Errorf("expected a missing project without errors, but got %+v", s)
+	}
+}
+
+func TestGetStatusNotARepo(t *testing.T) {
+	s := repo.GetStatus(config.DirAlias{Alias: "plain", Path: t.TempDir()})
+	if !s.Exists || s.IsRepo || s.Error != "" {
+		t.Errorf("expected an existing non-repo project, but got %+v", s)
+	}
+}
+
+func TestGetStatusAheadBehindDirty(t *testing.T) {
+	r, dir, hashes := newRepo(t, 3)
+	dirAlias := config.DirAlias{Alias: "proj", Path: dir}
+
+	s := repo.GetStatus(dirAlias)
+	if s.Branch != "master" || s.Upstream != "" || s.Dirty {
+		t.Errorf("expected a clean master without upstream, but got %+v", s)
+	}
+
+	setRemoteRef(t, r, hashes[0])
+	s = repo.GetStatus(dirAlias)
+	if s.Upstream != "origin/master" || s.Ahead != 2 || s.Behind != 0 {
+		t.Errorf("expected to be 2 ahead of origin/master, but got %+v", s)
+	}
+
+	// Move master back and the upstream forward
+	err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("master"), hashes[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setRemoteRef(t, r, hashes[2])
+	s = repo.GetStatus(dirAlias)
+	if s.Ahead != 0 || s.Behind != 1 {
+		t.Errorf("expected to be 1 behind origin/master, but got %+v", s)
+	}
+
+	// The worktree still has the contents of the third commit
+	if !s.Dirty {
+		t.Errorf("expected the worktree to be dirty, but got %+v", s)
+	}
+}
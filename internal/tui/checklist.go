@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ChecklistItem is a single entry of a Checklist.
+type ChecklistItem struct {
+	Label   string
+	Detail  string
+	Checked bool
+}
+
+// Checklist implements the tea.Model interface for reviewing a list of items
+// and deselecting the unwanted ones before confirming.
+//
+// Note that the fields `Items` and `Confirmed` are exported because they are
+// used by the main package.
+type Checklist struct {
+	Items     []ChecklistItem
+	Confirmed bool
+	title     string
+	cursor    int
+	offset    int
+	helpShown bool
+	done      bool
+}
+
+// checklistHeight is the number of items shown at the same time.
+const checklistHeight = 15
+
+// Init is one of the tea.Model interface methods but not used by the TUI.
+func (c Checklist) Init() tea.Cmd {
+	return nil
+}
+
+// Update is one of the tea.Model interface methods. It triggers updates to the
+// checklist and its state on keypresses.
+func (c Checklist) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			c.done = true
+			c.Confirmed = false
+			return c, tea.Quit
+
+		case "up", "k", "ctrl+p":
+			if c.cursor > 0 {
+				c.cursor--
+			}
+
+		case "down", "j", "ctrl+n":
+			if c.cursor < len(c.Items)-1 {
+				c.cursor++
+			}
+
+		case " ", "x":
+			if len(c.Items) > 0 {
+				// Items is shared with the caller's slice, so it's copied
+				// before being modified
+				items := make([]ChecklistItem, len(c.Items))
+				copy(items, c.Items)
+				items[c.cursor].Checked = !items[c.cursor].Checked
+				c.Items = items
+			}
+
+		case "a":
+			c.Items = toggleAll(c.Items)
+
+		case "enter":
+			c.done = true
+			c.Confirmed = true
+			return c, tea.Quit
+
+		case "?":
+			c.helpShown = !c.helpShown
+		}
+	}
+
+	if c.cursor < c.offset {
+		c.offset = c.cursor
+	}
+	if c.cursor >= c.offset+checklistHeight {
+		c.offset = c.cursor - checklistHeight + 1
+	}
+
+	return c, nil
+}
+
+// View is one of the tea.Model interface methods. It includes the rendering
+// logic.
+func (c Checklist) View() string {
+	if c.done {
+		return ""
+	}
+
+	maxLabelW, maxDetailW := 0, 0
+	for _, item := range c.Items {
+		maxLabelW = max(maxLabelW, len(item.Label))
+		maxDetailW = max(maxDetailW, len(item.Detail))
+	}
+
+	checked := 0
+	for _, item := range c.Items {
+		if item.Checked {
+			checked++
+		}
+	}
+
+	question := styles.question.Render(
+		alignQuestion(c.title, maxLabelW+maxDetailW+10),
+	)
+	counter := fmt.Sprintf("%d of %d selected", checked, len(c.Items))
+
+	rows := ""
+	end := min(c.offset+checklistHeight, len(c.Items))
+	for i := c.offset; i < end; i++ {
+		item := c.Items[i]
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+
+		fmtStr := fmt.Sprintf(" %%s %%-%ds  %%-%ds ", maxLabelW, maxDetailW)
+		row := fmt.Sprintf(fmtStr, box, item.Label, item.Detail)
+		if i == c.cursor {
+			rows += styles.selected.Render(row)
+		} else if item.Checked {
+			rows += row
+		} else {
+			rows += styles.rest.Render(row)
+		}
+		rows += "\n"
+	}
+
+	window := styles.window.Render(question + "\n\n" + counter + "\n\n" + rows)
+
+	help := ""
+	if c.helpShown {
+		help = checklistFullHelp
+	} else {
+		help = checklistShortHelp
+	}
+
+	return window + help + "\n\n"
+}
+
+func toggleAll(items []ChecklistItem) []ChecklistItem {
+	allChecked := true
+	for _, item := range items {
+		allChecked = allChecked && item.Checked
+	}
+
+	newItems := make([]ChecklistItem, len(items))
+	for i, item := range items {
+		item.Checked = !allChecked
+		newItems[i] = item
+	}
+
+	return newItems
+}
+
+// StartChecklist is the entry point for the checklist TUI which spawns the
+// bubbletea program. Items keep their initial Checked state.
+func StartChecklist(title string, items []ChecklistItem) *tea.Program {
+	return tea.NewProgram(Checklist{Items: items, title: title})
+}
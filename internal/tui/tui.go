@@ -8,7 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	l "github.com/charmbracelet/lipgloss"
-	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/pkg/gopen"
 )
 
 var styles = struct {
@@ -36,13 +36,14 @@ var styles = struct {
 // Model implements the tea.Model interface to be used as the model part of the
 // bubbletea program and includes fields that hold the program state.
 //
-// Note that the fields `Config` and `Selected` are exported because the are
-// used by the main package.
+// Note that the fields `Config`, `Selected`, and `Editor` are exported because
+// the are used by the main package. An empty `Editor` is the default editor.
 type Model struct {
-	Config      config.C
+	Config      gopen.Config
 	Selected    string
+	Editor      string
 	searchStr   string
-	results     []config.DirAlias
+	results     []gopen.Alias
 	selectedIdx int
 	helpShown   bool
 	done        bool
@@ -68,6 +69,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.searchStr = ""
 			m.results = searchAliases(m.Config.DirAliases, m.searchStr)
 
+		case "ctrl+e":
+			m.Editor = nextEditor(m.Config.EditorNames(), m.Editor)
+
 		case "up", "ctrl+p":
 			if m.selectedIdx > 0 {
 				m.selectedIdx--
@@ -124,11 +128,11 @@ func (m Model) View() string {
 	for i, a := range m.results {
 		if i == m.selectedIdx {
 			results += styles.selected.Render(
-				alignResult(a.Alias, a.Path, maxAliasW, maxPathW),
+				alignResult(a.Alias, a.Location(), maxAliasW, maxPathW),
 			)
 		} else {
 			results += styles.rest.Render(
-				alignResult(a.Alias, a.Path, maxAliasW, maxPathW),
+				alignResult(a.Alias, a.Location(), maxAliasW, maxPathW),
 			)
 		}
 
@@ -138,7 +142,16 @@ func (m Model) View() string {
 		}
 	}
 
-	window := styles.window.Render(question + "\n\n" + promptLine + "\n\n" + results)
+	editorLine := ""
+	if len(m.Config.Editors) > 0 {
+		editor := m.Editor
+		if editor == "" {
+			editor = "default"
+		}
+		editorLine = "\n" + styles.rest.Render("editor: "+editor)
+	}
+
+	window := styles.window.Render(question + "\n\n" + promptLine + "\n\n" + results + editorLine)
 
 	help := ""
 	if m.helpShown {
@@ -150,10 +163,25 @@ func (m Model) View() string {
 	return logo + "\n" + window + help + "\n\n"
 }
 
-func searchAliases(aliases []config.DirAlias, searchStr string) []config.DirAlias {
-	newResults := []config.DirAlias{}
+// nextEditor returns the editor profile after current in names, cycling back
+// to the default one ("") after the last.
+func nextEditor(names []string, current string) string {
+	if current == "" && len(names) > 0 {
+		return names[0]
+	}
+
+	for i, name := range names {
+		if name == current && i < len(names)-1 {
+			return names[i+1]
+		}
+	}
+	return ""
+}
+
+func searchAliases(aliases []gopen.Alias, searchStr string) []gopen.Alias {
+	newResults := []gopen.Alias{}
 	for _, a := range aliases {
-		if strings.Contains(a.Alias, searchStr) || strings.Contains(a.Path, searchStr) {
+		if strings.Contains(a.Alias, searchStr) || strings.Contains(a.Location(), searchStr) {
 			newResults = append(newResults, a)
 		}
 
@@ -164,7 +192,7 @@ func searchAliases(aliases []config.DirAlias, searchStr string) []config.DirAlia
 	return newResults
 }
 
-func initialModel(cfg config.C) Model {
+func initialModel(cfg gopen.Config) Model {
 	results := cfg.DirAliases
 	slices.Reverse(results)
 	if len(cfg.DirAliases) > 5 {
@@ -179,6 +207,6 @@ func initialModel(cfg config.C) Model {
 
 // StartTUI is the entry point for the interactive TUI which spawns the
 // bubbletea program.
-func StartTUI(cfg config.C) *tea.Program {
+func StartTUI(cfg gopen.Config) *tea.Program {
 	return tea.NewProgram(initialModel(cfg))
 }
@@ -3,7 +3,7 @@ package tui
 import (
 	"fmt"
 
-	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/pkg/gopen"
 )
 
 var gopenLogo = `
@@ -20,13 +20,14 @@ var fullHelp = `
 ctrl+n/↓  move selection down
 ctrl+p/↑  move selection up
 ctrl+w    clear search string
+ctrl+e    switch editor profile
 ctrl+c    quit`
 
 var shortHelp = `
 ?         show key bindings
 ctrl+c    quit`
 
-func calcMaxWidths(aliases []config.DirAlias) (int, int, int) {
+func calcMaxWidths(aliases []gopen.Alias) (int, int, int) {
 	maxAliasW := 0
 	maxPathW := 0
 
@@ -34,8 +35,8 @@ func calcMaxWidths(aliases []config.DirAlias) (int, int, int) {
 		if len(a.Alias) > maxAliasW {
 			maxAliasW = len(a.Alias)
 		}
-		if len(a.Path) > maxPathW {
-			maxPathW = len(a.Path)
+		if len(a.Location()) > maxPathW {
+			maxPathW = len(a.Location())
 		}
 	}
 
@@ -51,3 +52,18 @@ func alignResult(alias, path string, maxAliasW, maxPathW int) string {
 	fmtStr := fmt.Sprintf("  %%-%ds  %%-%ds ", maxAliasW, maxPathW+1)
 	return fmt.Sprintf(fmtStr, alias, path)
 }
+
+var checklistFullHelp = `
+?         hide key bindings
+j/↓       move cursor down
+k/↑       move cursor up
+space/x   toggle item
+a         toggle all items
+enter     confirm
+q/ctrl+c  cancel`
+
+var checklistShortHelp = `
+?         show key bindings
+space     toggle item
+enter     confirm
+q/ctrl+c  cancel`
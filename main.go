@@ -4,109 +4,109 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/waseem-medhat/gopen/internal/config"
 	"github.com/waseem-medhat/gopen/internal/tui"
+	"github.com/waseem-medhat/gopen/pkg/gopen"
 )
 
-var configDir = os.Getenv("HOME") + "/.config/gopen"
-var configPath = configDir + "/gopen.json"
+var store = gopen.NewStore(os.Getenv("HOME") + "/.config/gopen/gopen.json")
 
-func main() {
-	if len(os.Args) < 2 {
-		handleTUI()
-		return
-	}
-
-	switch os.Args[1] {
-	case "help", "h":
-		handleHelp()
-
-	case "init", "i":
-		handleInit()
-
-	case "editor", "e":
-		handleEditor()
-
-	case "alias", "a":
-		handleAlias()
-
-	case "git", "g":
-		handleGit()
-
-	case "remove", "r":
-		handleRemove()
-
-	case "custom", "c":
-		handleCustom()
-
-	default:
-		handleGopen()
-	}
+// command is an entry of the command table. Its names, including the
+// one-letter shorthands, are the reserved names of the config package, so
+// that they can't be used as aliases; main_test.go keeps the two in sync.
+type command struct {
+	names []string
+	run   func()
 }
 
-func handleInit() {
-	err := config.Init(configDir, configPath)
-	if err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err))
-	}
+var commands = []command{
+	{[]string{"help", "h"}, handleHelp},
+	{[]string{"init", "i"}, handleInit},
+	{[]string{"editor", "e"}, handleEditor},
+	{[]string{"which-editor"}, handleWhichEditor},
+	{[]string{"alias", "a"}, handleAlias},
+	{[]string{"git", "g"}, handleGit},
+	{[]string{"container"}, handleContainer},
+	{[]string{"mux"}, handleMux},
+	{[]string{"detach"}, handleDetach},
+	{[]string{"layout"}, handleLayout},
+	{[]string{"remove", "r"}, handleRemove},
+	{[]string{"rename"}, handleRename},
+	{[]string{"move"}, handleMove},
+	{[]string{"custom", "c"}, handleCustom},
+	{[]string{"open"}, handleOpen},
+	{[]string{"sync"}, handleSync},
+	{[]string{"status"}, handleStatus},
+	{[]string{"pull"}, handlePull},
+	{[]string{"tag"}, handleTag},
+	{[]string{"scan"}, handleScan},
+	{[]string{"import"}, handleImport},
+	{[]string{"export"}, handleExport},
+	{[]string{"doctor"}, handleDoctor},
 }
 
-func handleEditor() {
-	cfg, err := config.Read(configPath)
-	if err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err))
-		return
-	}
-
-	if len(os.Args) < 3 {
-		fmt.Println(cfg.EditorCmd)
+func main() {
+	if len(os.Args) < 2 {
+		handleTUI()
 		return
 	}
 
-	cfg.EditorCmd = os.Args[2]
-	err = config.Write(cfg, configPath)
-	if err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err))
+	for _, cmd := range commands {
+		if slices.Contains(cmd.names, os.Args[1]) {
+			cmd.run()
+			return
+		}
 	}
 
+	runOpen(os.Args[1:])
 }
 
 func handleAlias() {
-	cfg, err := config.Read(configPath)
+	fs := flag.NewFlagSet("alias", flag.ExitOnError)
+	host := fs.String("host", "", "SSH host that the path is on")
+	args := parseArgs(fs, os.Args[2:])
+
+	cfg, err := store.Load()
 	if err != nil {
 		fmt.Println(fmt.Errorf("error: %v", err))
 		return
 	}
 
-	switch len(os.Args) {
-	case 2:
+	switch len(args) {
+	case 0:
 		for _, fmtAlias := range cfg.ListAliases() {
 			fmt.Println(fmtAlias)
 		}
 
-	case 3:
+		shadowed := cfg.ShadowedAliases()
+		if len(shadowed) > 0 {
+			fmt.Printf("\nWarning: %v shadowed by commands (see `gopen doctor`)\n", strings.Join(shadowed, ", "))
+		}
+
+	case 1:
 		for _, dirAlias := range cfg.DirAliases {
-			if dirAlias.Alias == os.Args[2] {
-				fmt.Println(dirAlias.Path)
+			if dirAlias.Alias == args[0] {
+				fmt.Println(dirAlias.Location())
 				return
 			}
 		}
 		fmt.Println("Alias doesn't exist")
 
-	case 4:
-		cfg, err := cfg.AddAlias(os.Args[2], os.Args[3])
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-
-		err = config.Write(cfg, configPath)
-		if err != nil {
-			fmt.Println(fmt.Errorf("error: %v", err))
-		}
+	case 2:
+		updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+			if *host != "" {
+				return cfg.AddRemoteAlias(args[0], *host, args[1])
+			}
+			return cfg.AddAlias(args[0], args[1])
+		})
 
 	default:
 		fmt.Println("Too many arguments - exiting...")
@@ -114,98 +114,314 @@ func handleAlias() {
 }
 
 func handleGit() {
-	if len(os.Args) < 4 {
+	var opts config.GitOptions
+	fs := flag.NewFlagSet("git", flag.ExitOnError)
+	detect := fs.Bool("detect", false, "set the repo from the `origin` remote of the alias' path")
+	fs.StringVar(&opts.Branch, "branch", "", "branch to check out after cloning")
+	fs.IntVar(&opts.Depth, "depth", 0, "create a shallow clone with this many commits")
+	fs.BoolVar(&opts.Submodules, "submodules", false, "recursively clone submodules")
+	fs.StringVar(&opts.Auth, "auth", "", "auth method (ssh-agent, ssh-key:PATH, token-env:VAR, credential-helper)")
+
+	args := parseArgs(fs, os.Args[2:])
+	if *detect {
+		handleGitDetect(args)
+		return
+	}
+
+	if len(args) < 2 {
 		fmt.Println("Too few arguments - exiting...")
 		os.Exit(1)
 	}
-	alias := os.Args[2]
-	repo := os.Args[3]
+	alias := args[0]
+	repo := args[1]
+
+	saved := updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+		cfg, err := cfg.SetGitRepo(alias, repo)
+		if err != nil {
+			return cfg, err
+		}
+
+		// Clone options are only replaced when at least one of them is given so
+		// that changing the URL doesn't reset them
+		if fs.NFlag() > 0 {
+			return cfg.SetGitOptions(alias, opts)
+		}
+		return cfg, nil
+	})
+	if !saved {
+		os.Exit(1)
+	}
+
+	fmt.Printf("remote repo for `%v` was set to %v\n", alias, repo)
+}
+
+// handleGitDetect backfills the git repo of the given alias, or of every
+// alias without one if none is given, from the `origin` remote of its path.
+func handleGitDetect(args []string) {
+	if len(args) > 1 {
+		fmt.Println("Error: 'git --detect' takes at most one alias")
+		os.Exit(1)
+	}
 
-	cfg, err := config.Read(configPath)
+	updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+		var aliases []string
+		if len(args) == 1 {
+			aliases = args
+		} else {
+			for _, dirAlias := range cfg.DirAliases {
+				if dirAlias.GitRepo == "" {
+					aliases = append(aliases, dirAlias.Alias)
+				}
+			}
+		}
+
+		for _, alias := range aliases {
+			newCfg, remote, err := cfg.DetectGitRepo(alias)
+			if err != nil {
+				fmt.Printf("`%v`: %v\n", alias, err)
+				continue
+			}
+			cfg = newCfg
+			fmt.Printf("remote repo for `%v` was set to %v\n", alias, remote)
+		}
+		return cfg, nil
+	})
+}
+
+// handleOpen opens an alias explicitly, which is the only way to reach
+// aliases shadowed by a command.
+func handleOpen() {
+	runOpen(os.Args[2:])
+}
+
+// runOpen opens the alias in args, which may also hold an `-e` flag to use
+// another editor profile or command, and `--dry-run` to only print what
+// would be done.
+func runOpen(args []string) {
+	var opener gopen.Opener
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	fs.StringVar(&opener.Editor, "e", "", "editor profile or command to use instead of the default one")
+	fs.StringVar(&opener.Editor, "editor", "", "same as -e")
+	dryRun := fs.Bool("dry-run", false, "print what would be done without doing it")
+	args = parseArgs(fs, args)
+
+	if len(args) != 1 {
+		fmt.Println("Error: must provide one alias to open")
+		os.Exit(1)
+	}
+
+	var err error
+	opener.Config, err = store.Load()
 	if err != nil {
 		fmt.Println(fmt.Errorf("error: %v", err))
 		return
 	}
 
-	newCfg, err := cfg.SetGitRepo(alias, repo)
+	if *dryRun {
+		plan, err := opener.Plan(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		printPlan(plan)
+		return
+	}
+
+	err = openAlias(opener, args[0])
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
 	}
+}
 
-	err = config.Write(newCfg, configPath)
+// openAlias opens alias with opener, telling the user when its repo is
+// cloned first.
+func openAlias(opener gopen.Opener, alias string) error {
+	plan, err := opener.Plan(alias)
 	if err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err))
+		return err
+	}
+
+	if plan.Clone {
+		target := plan.Alias
+		if target.IsRemote() {
+			fmt.Printf("dir %v not found on %v\ntrying to clone %v\n", target.Path, target.Host, target.GitRepo)
+		} else {
+			fmt.Printf("dir %v not found\ntrying to clone %v\n", target.Path, target.GitRepo)
+		}
+		opener.Progress = os.Stdout
 	}
 
-	fmt.Printf("remote repo for `%v` was set to %v\n", os.Args[2], os.Args[3])
+	return opener.Exec(plan)
 }
 
-func handleGopen() {
-	cfg, err := config.Read(configPath)
-	if err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err))
-		return
+// printPlan prints what opening an alias would do, step by step.
+func printPlan(plan gopen.Plan) {
+	alias := plan.Alias
+	fmt.Printf("alias:    %v\n", alias.Alias)
+	fmt.Printf("path:     %v\n", alias.Location())
+	if alias.Container != "" {
+		fmt.Printf("in:       %v\n", alias.Container)
+	}
+	if plan.Clone {
+		fmt.Printf("clone:    %v (the path doesn't exist yet)\n", alias.GitRepo)
+	}
+	if plan.WorkDir != "" {
+		fmt.Printf("workdir:  %v\n", plan.WorkDir)
 	}
 
-	err = cfg.Gopen(os.Args[1])
-	if err != nil {
-		fmt.Println(err)
+	fmt.Printf("editor:   %v (from %v)\n", plan.Editor.CommandLine(), plan.EditorSource)
+	keys := make([]string, 0, len(plan.Editor.Env))
+	for k := range plan.Editor.Env {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		fmt.Printf("env:      %v=%v\n", k, plan.Editor.Env[k])
+	}
+
+	if plan.Detach {
+		fmt.Printf("detach:   yes, output goes to %v\n", plan.LogPath)
+	}
+
+	files := make([]string, 0, len(plan.Files))
+	for path := range plan.Files {
+		files = append(files, path)
+	}
+	slices.Sort(files)
+	for _, path := range files {
+		fmt.Printf("writes:   %v\n", path)
+	}
+
+	// Listed so that the dry run covers everything opening an alias does
+	fmt.Println("hooks:    none (gopen doesn't run hooks)")
+
+	fmt.Println("commands:")
+	for i, cmd := range plan.Commands {
+		var args []string
+		for _, arg := range cmd.Args {
+			if arg == "" || strings.ContainsAny(arg, " \t'\"$;&|") {
+				arg = strconv.Quote(arg)
+			}
+			args = append(args, arg)
+		}
+
+		line := strings.Join(args, " ")
+		if cmd.Dir != "" {
+			line += "  (in " + cmd.Dir + ")"
+		}
+		fmt.Printf("  %d. %v\n", i+1, line)
 	}
 }
 
-func handleRemove() {
-	cfg, err := config.Read(configPath)
+func handleDoctor() {
+	cfg, err := store.Load()
 	if err != nil {
 		fmt.Println(fmt.Errorf("error: %v", err))
 		return
 	}
 
+	problems := cfg.Doctor()
+	for _, p := range problems {
+		fmt.Printf("alias `%v` %v\n", p.Alias, p.Message)
+		fmt.Printf("  %v\n", p.Hint)
+	}
+
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return
+	}
+	os.Exit(1)
+}
+
+func handleRemove() {
 	if len(os.Args) != 3 {
 		fmt.Println("Error: must provide one alias to 'remove' command")
 		return
 	}
 
-	var newConfig config.C
-	newConfig.EditorCmd = cfg.EditorCmd
-	for _, dirAlias := range cfg.DirAliases {
-		if dirAlias.Alias != os.Args[2] {
-			newConfig.DirAliases = append(newConfig.DirAliases, dirAlias)
-		}
+	updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+		return cfg.RemoveAlias(os.Args[2])
+	})
+}
+
+func handleRename() {
+	if len(os.Args) != 4 {
+		fmt.Println("Error: usage is `gopen rename old new`")
+		os.Exit(1)
 	}
 
-	err = config.Write(newConfig, configPath)
-	if err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err))
+	saved := updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+		return cfg.RenameAlias(os.Args[2], os.Args[3])
+	})
+	if !saved {
+		os.Exit(1)
 	}
 }
 
-func handleCustom() {
-	cfg, err := config.Read(configPath)
-	if err != nil {
-		fmt.Println(fmt.Errorf("error: %v", err))
+func handleMove() {
+	fs := flag.NewFlagSet("move", flag.ExitOnError)
+	moveDir := fs.Bool("dir", false, "also move the directory on disk")
+
+	args := parseArgs(fs, os.Args[2:])
+	if len(args) != 2 {
+		fmt.Println("Error: usage is `gopen move alias newpath`")
+		os.Exit(1)
+	}
+	alias := args[0]
+
+	if !*moveDir {
+		saved := updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+			return cfg.MoveAlias(alias, args[1])
+		})
+		if !saved {
+			os.Exit(1)
+		}
 		return
 	}
 
+	var oldPath, newPath string
+	saved := updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+		if selected, err := cfg.Select([]string{alias}, ""); err == nil {
+			oldPath = selected[0].Path
+		}
+
+		cfg, err := cfg.MoveAliasOnDisk(alias, args[1])
+		if err != nil {
+			return cfg, fmt.Errorf("error: %v", err)
+		}
+
+		moved, _ := cfg.Select([]string{alias}, "")
+		newPath = moved[0].Path
+		return cfg, nil
+	})
+	if !saved {
+		os.Exit(1)
+	}
+
+	fmt.Printf("moved %v to %v\n", oldPath, newPath)
+}
+
+func handleCustom() {
 	switch len(os.Args) {
 	case 2:
+		cfg, err := store.Load()
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			return
+		}
 		fmt.Printf("Custom behaviour is set to :%v\n", cfg.CustomBehaviour)
 	case 3:
-		if os.Args[2] == "true" {
-			cfg.CustomBehaviour = true
-		} else if os.Args[2] == "false" {
-			cfg.CustomBehaviour = false
-		} else {
+		if os.Args[2] != "true" && os.Args[2] != "false" {
 			fmt.Println("Invalid argument, expected 'true' or 'false'")
+			return
 		}
-		err = config.Write(cfg, configPath)
-		if err != nil {
-			fmt.Println(fmt.Errorf("error: %v", err))
-		}
+		updateConfig(func(cfg gopen.Config) (gopen.Config, error) {
+			cfg.CustomBehaviour = os.Args[2] == "true"
+			return cfg, nil
+		})
 	default:
 		fmt.Println("Invalid number of arguments")
 	}
-
 }
 
 func handleHelp() {
@@ -214,37 +430,147 @@ func handleHelp() {
 Usage:
 
     gopen foo         cd into path assigned to alias 'foo' and run the editor cmd
+                        -e, --editor p  use editor profile (or command) 'p'
+                        --dry-run       print the path, editor, and commands
+                                        that would be run without running them
+    gopen open foo    Same as above, also works for aliases named like a command
     gopen cmd [args]  Run command 'cmd' (see Commands below)
 
 Commands:
 Can be abbreviated by the first letter ('gopen i' == 'gopen init')
 
-    init              Initialize a new config file (~/.config/gopen/gopen.json)
+    init              Initialize a new config file (~/.config/gopen/gopen.json),
+                      picking an editor found on PATH and optionally scanning
+                      a directory for projects
+                        --yes           don't ask, use the first editor found
+                        --scan dir      directory to scan for projects
+                        --depth n       levels to search below dir (default 2)
 
-    editor            Get editor command
+    editor            Get editor command (or the default editor profile)
     editor cmd        Set editor command to 'cmd'
+    editor list       List editor profiles ('*' marks the default)
+    editor add p 'c'  Add editor profile 'p' running command line 'c'
+                        --detach m      detach mode of the profile
+                        --env K=V       set env variable K (repeatable)
+    editor use [p]    Make profile 'p' the default (no 'p': use editor cmd)
+    editor use p foo  Open alias 'foo' with profile (or command) 'p' ('default'
+                      uses the default editor again)
+    editor rule       List editor rules
+    editor rule add pat p
+                      Open aliases whose root has a file matching 'pat' (a
+                      file name or glob, e.g. '*.sln') with editor 'p'
+    editor rule remove pat
+                      Remove the editor rule for 'pat'
+    which-editor foo  Show the editor that opens alias 'foo' and why
+    editor --resolved [foo]
+                      Show the editor that opens alias 'foo' (or any alias) and
+                      where it comes from: the alias, the config, $VISUAL,
+                      $EDITOR, or the platform default
+    editor remove p   Remove editor profile 'p'
 
     alias             List all saved aliases
     alias foo         Get path assigned to alias 'foo'
-    alias foo bar     Assign to alias 'foo' the path 'bar'
+    alias foo bar     Assign to alias 'foo' the path 'bar' (a directory or a file)
+                      (the 'origin' remote is saved too if 'bar' is a git repo)
+    alias foo bar --host h
+                      Assign to alias 'foo' the path 'bar' on SSH host 'h', opened
+                      with 'ssh -t h' or the editor's remote mode (e.g., VS Code)
 
     git foo bar       Set remote git repo for alias 'foo' to be 'bar'
                       This will try cloning the repo if the project doesn't exist
+                      Clone options (replace all previous options when given):
+                        --branch b      check out branch 'b'
+                        --depth n       shallow clone with 'n' commits
+                        --submodules    recursively clone submodules
+                        --auth method   ssh-agent, ssh-key:PATH, token-env:VAR,
+                                        or credential-helper
+    git foo --detect  Set remote git repo for alias 'foo' from its 'origin' remote
+    git --detect      Do the same for all aliases without a remote git repo
+
+    container foo c   Open alias 'foo' inside container 'c' instead of on the host,
+                      where 'c' is devcontainer, docker:NAME, or podman:NAME
+                        --workdir d     open directory 'd' inside the container
+                        --shell         start a shell instead of the editor
+    container foo     Get the container of alias 'foo'
+                        --clear         open 'foo' on the host again
+
+    mux               Get the terminal multiplexer that aliases are opened in
+    mux m             Open aliases in a session of multiplexer 'm' (tmux, zellij,
+                      or off), creating it in the project path if needed
+
+    layout foo w...   Set the windows of new sessions of alias 'foo', where each
+                      window is 'editor', 'shell', or a command to run
+                        --zellij-layout l   use zellij layout 'l' instead
+    layout foo        Get the windows of alias 'foo' (default: editor, shell)
+                        --clear         go back to the default windows
+
+    detach            Get the detach mode of the editor and of each alias
+    detach m          Set the detach mode to 'm': 'always' starts the editor in
+                      the background with its output logged, 'never' waits for
+                      it, and 'auto' (default) only detaches GUI editors
+    detach m foo      Set the detach mode of alias 'foo' to 'm' ('default'
+                      uses the editor's mode again)
 
     remove foo        Remove alias 'foo' from the config
 
+    rename foo bar    Rename alias 'foo' to 'bar', keeping its git repo and tags
+
+    move foo bar      Assign to alias 'foo' the path 'bar', keeping everything else
+                        --dir           also move the directory to 'bar'
+
+    sync              Clone all git-backed projects whose path doesn't exist
+                        --dry-run       only list the repos that would be cloned
+                        --jobs n        clone 'n' repos at a time (default 4)
+
+    status            Show the git status of all projects
+                        --json          print the statuses as JSON
+                        --dirty         only show projects with uncommitted changes
+                        --missing       only show projects whose path doesn't exist
+
+    pull [foo...]     Fetch and fast-forward the git repos of the given aliases
+                      (or all of them), skipping dirty or diverged ones
+                        --tag t         pull the projects tagged with 't'
+
+    tag foo           List the tags of alias 'foo'
+    tag foo t1 t2     Set the tags of alias 'foo' to 't1' and 't2'
+                        --clear         remove all tags from alias 'foo'
+
+    scan dir          Find projects under 'dir' (by markers like .git or go.mod)
+                      and pick the ones to add as aliases
+                        --depth n       search 'n' levels below 'dir' (default 2)
+                        --yes           add all found projects without reviewing
+
+    import --from tool file
+                      Import aliases from another tool's data file, where 'tool'
+                      is zoxide, autojump, vscode, projectile, fasd, or bundle
+                        --on-conflict p skip (default), overwrite, or rename
+                                        aliases that already exist
+                        --limit n       only import the 'n' highest-ranked paths
+                        --yes           import without asking for confirmation
+                        --root dir      projects directory for bundle paths
+
+    export --format f Print all aliases as 'f', one of sh, fish (shell aliases),
+                      csv, json, markdown (project index), or bundle (portable
+                      aliases to import on another machine)
+                        --output file   write to 'file' instead of stdout
+                        --root dir      write paths under 'dir' as $ROOT/...
+                        --strip         leave paths out of the bundle
+
     custom            Get custom behaviour
     custom bool       Set custom behaviour to true or false
                       (Custom behavior omits the path from the command execution,
                       running 'cmd' instead of 'cmd path')
 
+    doctor            Check the config for problems, like aliases shadowed by
+                      commands or paths that changed from a file to a directory
+
     help              Print this help message
 
 `)
 }
 
 func handleTUI() {
-	cfg, err := config.Read(configPath)
+	cfg, err := store.Load()
 	if err != nil {
 		fmt.Println("Couldn't find config file\nRun `gopen init` to initialize one.")
 		return
@@ -255,11 +581,6 @@ func handleTUI() {
 		return
 	}
 
-	if cfg.EditorCmd == "" {
-		fmt.Println("Editor command not set\nSet it with `gopen editor youreditor`")
-		return
-	}
-
 	p := tui.StartTUI(cfg)
 	m, err := p.Run()
 	if err != nil {
@@ -270,10 +591,77 @@ func handleTUI() {
 	if tuiModel, ok := m.(tui.Model); ok {
 		alias := tuiModel.Selected
 		if alias != "" {
-			err = tuiModel.Config.Gopen(alias)
+			opener := gopen.Opener{Config: tuiModel.Config, Editor: tuiModel.Editor}
+			err = openAlias(opener, alias)
 			if err != nil {
 				fmt.Println(err)
 			}
 		}
 	}
 }
+
+// updateConfig changes the config file with fn while holding its lock, so
+// that other gopen processes can't overwrite the change. Errors from fn are
+// printed as they are and others as I/O errors. It reports whether the config
+// was saved.
+func updateConfig(fn func(gopen.Config) (gopen.Config, error)) bool {
+	var fnErr error
+	err := store.Update(func(cfg gopen.Config) (gopen.Config, error) {
+		cfg, fnErr = fn(cfg)
+		return cfg, fnErr
+	})
+	if fnErr != nil {
+		fmt.Println(fnErr)
+	} else if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+	}
+	return err == nil
+}
+
+// parseArgs parses the flags in args using fs and returns the remaining
+// positional arguments. Unlike fs.Parse, flags may come after positional
+// arguments (e.g., `gopen git foo bar --depth 1`).
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		// Errors are handled by the flag set's error handling mode
+		_ = fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// stdin is shared by the prompts so that input read ahead by one isn't lost
+// to the next.
+var stdin = bufio.NewReader(os.Stdin)
+
+// confirm asks a yes/no question on stdin and reports whether it was answered
+// with yes. Anything other than "y" or "yes" counts as no.
+func confirm(question string) bool {
+	fmt.Print(question + " [y/N] ")
+
+	answer, _ := stdin.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// prompt asks question on stdin and returns the answer, or def if the answer
+// is empty.
+func prompt(question string, def string) string {
+	if def != "" {
+		question += " [" + def + "]"
+	}
+	fmt.Print(question + " ")
+
+	answer, _ := stdin.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def
+	}
+	return answer
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+func TestCommandsAreReserved(t *testing.T) {
+	var names []string
+	for _, cmd := range commands {
+		names = append(names, cmd.names...)
+	}
+	slices.Sort(names)
+
+	reserved := config.Reserved()
+	slices.Sort(reserved)
+
+	if !slices.Equal(names, reserved) {
+		t.Errorf("Expected the command names to be the reserved names of the config package\n%q\nbut got\n%q", reserved, names)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+func handleMux() {
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	switch len(os.Args) {
+	case 2:
+		if cfg.Multiplexer == "" {
+			fmt.Println("off")
+		} else {
+			fmt.Println(cfg.Multiplexer)
+		}
+
+	case 3:
+		multiplexer := os.Args[2]
+		if multiplexer == "off" {
+			multiplexer = ""
+		}
+
+		saved := updateConfig(func(cfg config.C) (config.C, error) {
+			cfg, err := cfg.SetMultiplexer(multiplexer)
+			if err != nil {
+				return cfg, fmt.Errorf("error: %v", err)
+			}
+			return cfg, nil
+		})
+		if !saved {
+			os.Exit(1)
+		}
+
+	default:
+		fmt.Println("Too many arguments - exiting...")
+	}
+}
+
+func handleLayout() {
+	var opts config.MuxOptions
+	fs := flag.NewFlagSet("layout", flag.ExitOnError)
+	clearLayout := fs.Bool("clear", false, "go back to the default layout")
+	fs.StringVar(&opts.ZellijLayout, "zellij-layout", "", "zellij layout name or file to use instead of the windows")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) == 0 {
+		fmt.Println("Error: must provide an alias to 'layout' command")
+		os.Exit(1)
+	}
+	alias := args[0]
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	if len(args) == 1 && opts.ZellijLayout == "" && !*clearLayout {
+		selected, err := cfg.Select([]string{alias}, "")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		windows := selected[0].Windows
+		if len(windows) == 0 {
+			windows = config.DefaultWindows
+		}
+		for i, w := range windows {
+			fmt.Printf("%d: %v\n", i+1, w)
+		}
+		if selected[0].ZellijLayout != "" {
+			fmt.Printf("zellij layout: %v\n", selected[0].ZellijLayout)
+		}
+		return
+	}
+
+	opts.Windows = args[1:]
+	saved := updateConfig(func(cfg config.C) (config.C, error) {
+		cfg, err := cfg.SetMuxOptions(alias, opts)
+		if err != nil {
+			return cfg, fmt.Errorf("error: %v", err)
+		}
+		return cfg, nil
+	})
+	if !saved {
+		os.Exit(1)
+	}
+}
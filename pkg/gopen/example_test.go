@@ -0,0 +1,78 @@
+package gopen_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+)
+
+func ExampleStore() {
+	dir, err := os.MkdirTemp("", "gopen")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := gopen.NewStore(filepath.Join(dir, "gopen.json"))
+	err = store.Init(gopen.Config{EditorCmd: "nvim"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = store.Update(func(cfg gopen.Config) (gopen.Config, error) {
+		cfg, err := cfg.AddAlias("blog", dir)
+		if err != nil {
+			return cfg, err
+		}
+		return cfg.SetTags("blog", []string{"personal"})
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	aliases, err := store.List("personal")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, a := range aliases {
+		fmt.Println(a.Alias, a.Tags)
+	}
+
+	_, err = store.Get("work")
+	fmt.Println(err)
+	// Output:
+	// blog [personal]
+	// alias doesn't exist: work
+}
+
+func ExampleOpener_Plan() {
+	dir, err := os.MkdirTemp("", "gopen")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg, err := gopen.Config{EditorCmd: "nvim"}.AddAlias("blog", dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opener := gopen.Opener{Config: cfg, Editor: "hx"}
+	plan, err := opener.Plan("blog")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("editor:", plan.Editor.CommandLine(), "from", plan.EditorSource)
+	fmt.Println("clone:", plan.Clone)
+	for _, cmd := range plan.Commands {
+		fmt.Println("run:", cmd.Args[0], cmd.Dir == dir)
+	}
+	// Output:
+	// editor: hx from override
+	// clone: false
+	// run: hx true
+}
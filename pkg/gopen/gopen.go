@@ -0,0 +1,97 @@
+// Package gopen is the public API of Gopen, for tools that want to read and
+// change Gopen aliases or open them the way the `gopen` command does. A Store
+// loads, saves, and queries the config file, and an Opener resolves the
+// editor of an alias and launches it.
+//
+// # Compatibility
+//
+// This package follows semantic versioning together with the module: once
+// the module is tagged v1.0.0, the following won't be removed or changed in
+// incompatible ways until the next major version, which would be published
+// under a new module path as Go requires:
+//
+//   - Store and Opener, their fields, and their methods
+//   - ErrNotFound and the constants
+//   - the names of the other types, like Config and Alias, and their struct
+//     fields
+//
+// The other types are aliases of types defined in internal/, and only their
+// fields are covered: their methods (like Config.AddAlias) come along with
+// the internal types and can change in any release. Use Store and Opener
+// where they do the job.
+//
+// New identifiers, methods, struct fields, and config fields may be added in
+// minor versions, so don't rely on unkeyed struct literals. Before v1.0.0,
+// breaking changes are called out in the release notes.
+package gopen
+
+import "github.com/waseem-medhat/gopen/internal/config"
+
+// Config is the content of a Gopen config file: the editor settings and the
+// aliases. Its methods return a changed copy instead of modifying it.
+type Config = config.C
+
+// Alias is a saved alias and everything about how it's opened.
+type Alias = config.DirAlias
+
+// GitOptions, ContainerOptions, and MuxOptions are embedded in Alias and
+// hold how its repo is cloned, which container it's opened in, and the
+// windows of its multiplexer session.
+type (
+	GitOptions       = config.GitOptions
+	ContainerOptions = config.ContainerOptions
+	MuxOptions       = config.MuxOptions
+)
+
+// Kinds of paths an alias can point to, as recorded in Alias.Kind.
+const (
+	KindDir  = config.KindDir
+	KindFile = config.KindFile
+)
+
+// Detach modes of Config.Detach, Alias.Detach, and EditorProfile.Detach.
+const (
+	DetachAuto   = config.DetachAuto
+	DetachAlways = config.DetachAlways
+	DetachNever  = config.DetachNever
+)
+
+// EditorProfile is a named editor command with its arguments, detach mode,
+// and environment.
+type EditorProfile = config.EditorProfile
+
+// EditorRule picks the editor of the aliases whose project root has a file
+// matching its pattern, as added with Config.AddEditorRule.
+type EditorRule = config.EditorRule
+
+// Problem is an issue with an alias found by Config.Doctor.
+type Problem = config.Problem
+
+// OpenOptions tweak how Config.Plan and Config.GopenWith open an alias.
+// Opener sets them from its fields, so most callers don't need them.
+type OpenOptions = config.OpenOptions
+
+// Plan is everything that opening an alias does, as returned by
+// Opener.Plan.
+type Plan = config.Plan
+
+// Runner runs the commands that open aliases.
+type Runner = config.Runner
+
+// FS is the part of the filesystem that is looked at to open an alias.
+type FS = config.FS
+
+// Cloner clones the git repos of aliases whose paths don't exist.
+type Cloner = config.Cloner
+
+// Sources of the editor returned by Opener.ResolveEditor, from the highest
+// precedence to the lowest.
+const (
+	EditorFromOverride = config.EditorFromOverride
+	EditorFromAlias    = config.EditorFromAlias
+	EditorFromRule     = config.EditorFromRule
+	EditorFromConfig   = config.EditorFromConfig
+	EditorFromVisual   = config.EditorFromVisual
+	EditorFromEditor   = config.EditorFromEditor
+	EditorFromPlatform = config.EditorFromPlatform
+)
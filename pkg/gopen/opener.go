@@ -0,0 +1,66 @@
+package gopen
+
+import (
+	"io"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// Opener opens the aliases of Config like the `gopen` command does. Its zero
+// fields have defaults: the editor resolved for each alias, and real
+// processes, filesystem, git clones, and the streams of the process. Progress
+// gets the progress of clones and stays quiet if it's nil.
+type Opener struct {
+	Config Config
+	// Editor is an editor profile or command that overrides the resolved
+	// one, like `gopen -e`
+	Editor string
+
+	Runner   Runner
+	FS       FS
+	Cloner   Cloner
+	Stdin    io.Reader
+	Stdout   io.Writer
+	Stderr   io.Writer
+	Progress io.Writer
+}
+
+func (o Opener) options() config.OpenOptions {
+	return config.OpenOptions{
+		Editor:   o.Editor,
+		Runner:   o.Runner,
+		FS:       o.FS,
+		Cloner:   o.Cloner,
+		Stdin:    o.Stdin,
+		Stdout:   o.Stdout,
+		Stderr:   o.Stderr,
+		Progress: o.Progress,
+	}
+}
+
+// ResolveEditor returns the editor that opens alias, and which of the
+// EditorFrom sources it came from.
+func (o Opener) ResolveEditor(alias string) (EditorProfile, string, error) {
+	return o.Config.ResolveEditorWith(alias, o.options())
+}
+
+// Plan works out how alias would be opened without opening it. Only
+// read-only checks are run, like whether the path exists.
+func (o Opener) Plan(alias string) (Plan, error) {
+	return o.Config.Plan(alias, o.options())
+}
+
+// Exec carries out a plan returned by Plan.
+func (o Opener) Exec(plan Plan) error {
+	return plan.Exec(o.options())
+}
+
+// Open opens alias. It's the same as Exec after Plan.
+func (o Opener) Open(alias string) error {
+	plan, err := o.Plan(alias)
+	if err != nil {
+		return err
+	}
+
+	return o.Exec(plan)
+}
@@ -0,0 +1,101 @@
+package gopen
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+)
+
+// ErrNotFound is returned (wrapped) when an alias doesn't exist.
+var ErrNotFound = errors.New("alias doesn't exist")
+
+// Store is a Gopen config file.
+type Store struct {
+	path string
+}
+
+// NewStore returns the store of the config file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultStore returns the store of the config file that the `gopen` command
+// uses, ~/.config/gopen/gopen.json.
+func DefaultStore() (*Store, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStore(filepath.Join(home, ".config", "gopen", "gopen.json")), nil
+}
+
+// Path returns the path of the config file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Init creates the config file with cfg, and its directory if needed. It
+// fails with an error matching os.ErrExist if the file already exists.
+func (s *Store) Init(cfg Config) error {
+	return config.InitWith(cfg, filepath.Dir(s.path), s.path)
+}
+
+// Load reads the config file.
+func (s *Store) Load() (Config, error) {
+	return config.Read(s.path)
+}
+
+// Save overwrites the config file with cfg. It waits for running updates to
+// finish, but changes they make after cfg was loaded are lost; use Update to
+// keep them.
+func (s *Store) Save(cfg Config) error {
+	return config.Replace(cfg, s.path)
+}
+
+// Update loads the config, changes it with fn, and saves the result unless
+// fn fails. The file is locked meanwhile, so concurrent updates (from other
+// processes too) run one after the other instead of overwriting each other.
+func (s *Store) Update(fn func(Config) (Config, error)) error {
+	return config.Update(s.path, fn)
+}
+
+// List returns the aliases in the config file, or only the ones tagged tag if
+// it isn't empty.
+func (s *Store) List(tag string) ([]Alias, error) {
+	cfg, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if tag == "" {
+		return cfg.DirAliases, nil
+	}
+
+	var tagged []Alias
+	for _, a := range cfg.DirAliases {
+		if slices.Contains(a.Tags, tag) {
+			tagged = append(tagged, a)
+		}
+	}
+	return tagged, nil
+}
+
+// Get returns the alias called name, or an error wrapping ErrNotFound.
+func (s *Store) Get(name string) (Alias, error) {
+	cfg, err := s.Load()
+	if err != nil {
+		return Alias{}, err
+	}
+
+	for _, a := range cfg.DirAliases {
+		if a.Alias == name {
+			return a, nil
+		}
+	}
+	return Alias{}, fmt.Errorf("%w: %v", ErrNotFound, name)
+}
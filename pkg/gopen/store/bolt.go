@@ -0,0 +1,200 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+	bolt "go.etcd.io/bbolt"
+)
+
+var aliasesBucket = []byte("aliases")
+
+// Bolt is a Store backed by a bbolt database, for alias sets too large to
+// rewrite a JSON file on every change. Aliases are kept as JSON under their
+// names, so List returns them sorted by name. The database is locked while
+// the store is open, so Watch only sees changes made through this store.
+//
+// Changes never wait for watchers: a watcher that falls watchBuffer events
+// behind is disconnected by closing its channel.
+type Bolt struct {
+	db *bolt.DB
+
+	mu       sync.Mutex
+	watchers []*watcher
+	closed   chan struct{}
+}
+
+// watchBuffer is how many events a Bolt watcher can fall behind.
+const watchBuffer = 64
+
+type watcher struct {
+	events chan Event
+}
+
+// OpenBolt opens the bbolt database at path, creating it if needed. It waits
+// up to a second for other processes to close it.
+func OpenBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(aliasesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Bolt{db: db, closed: make(chan struct{})}, nil
+}
+
+func (s *Bolt) List() ([]gopen.Alias, error) {
+	var aliases []gopen.Alias
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(aliasesBucket).ForEach(func(_, v []byte) error {
+			var a gopen.Alias
+			err := json.Unmarshal(v, &a)
+			if err != nil {
+				return err
+			}
+			aliases = append(aliases, a)
+			return nil
+		})
+	})
+	return aliases, err
+}
+
+func (s *Bolt) Get(name string) (gopen.Alias, error) {
+	var a gopen.Alias
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(aliasesBucket).Get([]byte(name))
+		if v == nil {
+			return fmt.Errorf("%w: %v", gopen.ErrNotFound, name)
+		}
+		return json.Unmarshal(v, &a)
+	})
+	return a, err
+}
+
+func (s *Bolt) Put(a gopen.Alias) error {
+	a, err := config.CheckAlias(a)
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	// The change and its event happen under s.mu, so that watchers get the
+	// events in the order the changes were committed
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(aliasesBucket).Put([]byte(a.Alias), v)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notify(Event{Op: OpPut, Alias: a})
+	return nil
+}
+
+func (s *Bolt) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(aliasesBucket)
+		if b.Get([]byte(name)) == nil {
+			return fmt.Errorf("%w: %v", gopen.ErrNotFound, name)
+		}
+		return b.Delete([]byte(name))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notify(Event{Op: OpDelete, Alias: gopen.Alias{Alias: name}})
+	return nil
+}
+
+func (s *Bolt) Watch(ctx context.Context) (<-chan Event, error) {
+	w := &watcher{events: make(chan Event, watchBuffer)}
+
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+		s.mu.Unlock()
+		return nil, errors.New("store is closed")
+	default:
+	}
+	s.watchers = append(s.watchers, w)
+	s.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.closed:
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.disconnect(w)
+	}()
+
+	return w.events, nil
+}
+
+// disconnect removes w from the watchers and closes its channel, unless
+// that already happened. s.mu must be held.
+func (s *Bolt) disconnect(w *watcher) {
+	i := slices.Index(s.watchers, w)
+	if i == -1 {
+		return
+	}
+
+	s.watchers = slices.Delete(s.watchers, i, i+1)
+	close(w.events)
+}
+
+// notify sends ev to the watchers without blocking, disconnecting the ones
+// whose buffer is full. s.mu must be held.
+func (s *Bolt) notify(ev Event) {
+	for _, w := range slices.Clone(s.watchers) {
+		select {
+		case w.events <- ev:
+		default:
+			s.disconnect(w)
+		}
+	}
+}
+
+// Close closes the database and the channels of the watchers.
+func (s *Bolt) Close() error {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+		for len(s.watchers) > 0 {
+			s.disconnect(s.watchers[0])
+		}
+	}
+	s.mu.Unlock()
+
+	return s.db.Close()
+}
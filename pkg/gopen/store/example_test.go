@@ -0,0 +1,43 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+	"github.com/waseem-medhat/gopen/pkg/gopen/store"
+)
+
+func ExampleBolt() {
+	dir, err := os.MkdirTemp("", "gopen")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := store.OpenBolt(filepath.Join(dir, "aliases.db"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := s.Watch(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = s.Put(gopen.Alias{Alias: "shop", Path: "/code/shop"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ev := <-events
+	fmt.Println(ev.Op, ev.Alias.Alias, ev.Alias.Path)
+	// Output:
+	// put shop /code/shop
+}
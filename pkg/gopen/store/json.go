@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+)
+
+// DefaultPollInterval is how often JSON.Watch checks the config file for
+// changes unless JSON.PollInterval is set.
+const DefaultPollInterval = time.Second
+
+// JSON is a Store backed by a Gopen config file. The rest of the config, like
+// the editor settings, is kept as it is. Since the file can also be changed
+// by other processes (or by hand), Watch polls it every PollInterval. Put
+// and Delete go through gopen.Store.Update, which locks the file.
+type JSON struct {
+	File         *gopen.Store
+	PollInterval time.Duration
+}
+
+// NewJSON returns the store of the config file at path, which has to be
+// created first (e.g., with gopen.Store.Init).
+func NewJSON(path string) *JSON {
+	return &JSON{File: gopen.NewStore(path)}
+}
+
+func (s *JSON) List() ([]gopen.Alias, error) {
+	return s.File.List("")
+}
+
+func (s *JSON) Get(name string) (gopen.Alias, error) {
+	return s.File.Get(name)
+}
+
+func (s *JSON) Put(a gopen.Alias) error {
+	a, err := config.CheckAlias(a)
+	if err != nil {
+		return err
+	}
+
+	return s.File.Update(func(cfg gopen.Config) (gopen.Config, error) {
+		i := slices.IndexFunc(cfg.DirAliases, func(d gopen.Alias) bool { return d.Alias == a.Alias })
+		if i == -1 {
+			cfg.DirAliases = append(slices.Clip(cfg.DirAliases), a)
+		} else {
+			cfg.DirAliases = slices.Clone(cfg.DirAliases)
+			cfg.DirAliases[i] = a
+		}
+		return cfg, nil
+	})
+}
+
+func (s *JSON) Delete(name string) error {
+	return s.File.Update(func(cfg gopen.Config) (gopen.Config, error) {
+		i := slices.IndexFunc(cfg.DirAliases, func(d gopen.Alias) bool { return d.Alias == name })
+		if i == -1 {
+			return cfg, fmt.Errorf("%w: %v", gopen.ErrNotFound, name)
+		}
+		cfg.DirAliases = slices.Delete(slices.Clone(cfg.DirAliases), i, i+1)
+		return cfg, nil
+	})
+}
+
+func (s *JSON) Watch(ctx context.Context) (<-chan Event, error) {
+	last, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := s.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// The file may be read while it's being written, so errors
+			// are retried on the next tick
+			current, err := s.List()
+			if err != nil {
+				continue
+			}
+
+			for _, ev := range diff(last, current) {
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+			last = current
+		}
+	}()
+
+	return events, nil
+}
+
+// Close does nothing since the file is only open while it's read or written.
+func (s *JSON) Close() error {
+	return nil
+}
+
+// diff returns the events that turn the aliases in before into the ones in
+// after: puts in the order of after, then deletes by name.
+func diff(before []gopen.Alias, after []gopen.Alias) []Event {
+	old := map[string]gopen.Alias{}
+	for _, a := range before {
+		old[a.Alias] = a
+	}
+
+	var events []Event
+	kept := map[string]bool{}
+	for _, a := range after {
+		kept[a.Alias] = true
+		if prev, ok := old[a.Alias]; !ok || !reflect.DeepEqual(prev, a) {
+			events = append(events, Event{Op: OpPut, Alias: a})
+		}
+	}
+
+	var deleted []string
+	for name := range old {
+		if !kept[name] {
+			deleted = append(deleted, name)
+		}
+	}
+	sort.Strings(deleted)
+	for _, name := range deleted {
+		events = append(events, Event{Op: OpDelete, Alias: gopen.Alias{Alias: name}})
+	}
+
+	return events
+}
@@ -0,0 +1,64 @@
+// Package store defines Store, the interface of the places Gopen aliases can
+// be kept in, and its implementations: JSON, the config file that the
+// `gopen` command uses, and Bolt, a bbolt database for large alias sets.
+//
+// Every implementation must pass the conformance suite in the storetest
+// package.
+package store
+
+import (
+	"context"
+	"io"
+
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+)
+
+// Store keeps aliases by name.
+type Store interface {
+	// List returns all aliases. The order is up to the implementation.
+	List() ([]gopen.Alias, error)
+	// Get returns the alias called name, or an error wrapping
+	// gopen.ErrNotFound.
+	Get(name string) (gopen.Alias, error)
+	// Put adds a, or replaces the alias with the same name. Aliases are
+	// validated like the ones added through gopen.Config, and their local
+	// paths are made absolute.
+	Put(a gopen.Alias) error
+	// Delete removes the alias called name, or returns an error wrapping
+	// gopen.ErrNotFound.
+	Delete(name string) error
+	// Watch returns a channel that gets an Event for every change to the
+	// aliases until ctx is done or the store is closed, when the channel is
+	// closed. The channel should be drained: implementations may close it
+	// early for watchers that fall behind instead of making changes wait.
+	Watch(ctx context.Context) (<-chan Event, error)
+
+	io.Closer
+}
+
+// Op is the kind of change in an Event.
+type Op int
+
+const (
+	// OpPut means that an alias was added or replaced.
+	OpPut Op = iota + 1
+	// OpDelete means that an alias was removed.
+	OpDelete
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpPut:
+		return "put"
+	case OpDelete:
+		return "delete"
+	}
+	return "unknown"
+}
+
+// Event is a change to the aliases of a Store. Alias is the new alias for
+// OpPut and is only named for OpDelete.
+type Event struct {
+	Op    Op
+	Alias gopen.Alias
+}
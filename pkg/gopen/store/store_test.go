@@ -0,0 +1,57 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+	"github.com/waseem-medhat/gopen/pkg/gopen/store"
+	"github.com/waseem-medhat/gopen/pkg/gopen/store/storetest"
+)
+
+func TestJSON(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		path := filepath.Join(t.TempDir(), "gopen.json")
+		err := gopen.NewStore(path).Init(gopen.Config{EditorCmd: "nvim"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := store.NewJSON(path)
+		s.PollInterval = 10 * time.Millisecond
+		return s
+	})
+}
+
+func TestJSONKeepsSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gopen.json")
+	file := gopen.NewStore(path)
+	err := file.Init(gopen.Config{EditorCmd: "nvim", CustomBehaviour: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.NewJSON(path).Put(gopen.Alias{Alias: "shop", Path: "/code/shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := file.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.EditorCmd != "nvim" || !cfg.CustomBehaviour || len(cfg.DirAliases) != 1 {
+		t.Errorf("Expected the settings to be kept next to the alias, but got %+v", cfg)
+	}
+}
+
+func TestBolt(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		s, err := store.OpenBolt(filepath.Join(t.TempDir(), "gopen.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
@@ -0,0 +1,361 @@
+// Package storetest is the conformance suite that every store.Store
+// implementation must pass.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+	"github.com/waseem-medhat/gopen/pkg/gopen/store"
+)
+
+// Timeout is how long Run waits for Watch events.
+var Timeout = 5 * time.Second
+
+// Run runs the conformance suite against the stores returned by newStore,
+// which must be empty and are closed by Run.
+func Run(t *testing.T, newStore func(t *testing.T) store.Store) {
+	tests := []struct {
+		name string
+		run  func(*testing.T, store.Store)
+	}{
+		{"Empty", testEmpty},
+		{"PutGet", testPutGet},
+		{"PutReplaces", testPutReplaces},
+		{"PutNeedsName", testPutNeedsName},
+		{"PutValidates", testPutValidates},
+		{"ConcurrentPut", testConcurrentPut},
+		{"List", testList},
+		{"Delete", testDelete},
+		{"Watch", testWatch},
+		{"WatchOrder", testWatchOrder},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newStore(t)
+			defer func() {
+				err := s.Close()
+				if err != nil {
+					t.Errorf("Close: %v", err)
+				}
+			}()
+			test.run(t, s)
+		})
+	}
+}
+
+// fullAlias returns an alias with most of its fields set, to check that
+// they're all kept.
+func fullAlias(name string) gopen.Alias {
+	return gopen.Alias{
+		Alias:   name,
+		Path:    "/code/" + name,
+		Kind:    gopen.KindDir,
+		GitRepo: "https://example.com/" + name + ".git",
+		Tags:    []string{"work", "go"},
+		Detach:  gopen.DetachNever,
+		Editor:  "nvim",
+		GitOptions: gopen.GitOptions{
+			Branch: "main",
+			Depth:  1,
+		},
+		MuxOptions: gopen.MuxOptions{Windows: []string{"editor", "shell"}},
+	}
+}
+
+func testEmpty(t *testing.T, s store.Store) {
+	aliases, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != 0 {
+		t.Errorf("Expected no aliases, but got %v", aliases)
+	}
+
+	_, err = s.Get("nope")
+	if !errors.Is(err, gopen.ErrNotFound) {
+		t.Errorf("Expected gopen.ErrNotFound, but got %v", err)
+	}
+}
+
+func testPutGet(t *testing.T, s store.Store) {
+	expected := fullAlias("shop")
+	err := s.Put(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := s.Get("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected\n%+v\nbut got\n%+v", expected, actual)
+	}
+}
+
+func testPutReplaces(t *testing.T, s store.Store) {
+	err := s.Put(fullAlias("shop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := gopen.Alias{Alias: "shop", Path: "/elsewhere"}
+	err = s.Put(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := s.Get("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, actual)
+	}
+
+	aliases, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != 1 {
+		t.Errorf("Expected one alias after replacing, but got %v", aliases)
+	}
+}
+
+func testPutNeedsName(t *testing.T, s store.Store) {
+	err := s.Put(gopen.Alias{Path: "/code/nameless"})
+	if err == nil {
+		t.Error("Expected an error for an alias without a name, but got nil")
+	}
+}
+
+func testPutValidates(t *testing.T, s store.Store) {
+	invalid := []gopen.Alias{
+		{Alias: "sync", Path: "/code/sync"},
+		{Alias: "shop", Path: "/code/shop", Kind: "symlink"},
+		{Alias: "shop", Path: "/code/shop", Detach: "sometimes"},
+		{Alias: "shop", Host: "devvm", Path: "code/shop"},
+	}
+	for _, a := range invalid {
+		err := s.Put(a)
+		if err == nil {
+			t.Errorf("Expected an error for %+v, but got nil", a)
+		}
+	}
+
+	err := s.Put(gopen.Alias{Alias: "shop", Path: "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := s.Get("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filepath.IsAbs(actual.Path) {
+		t.Errorf("Expected the path to be made absolute, but got %v", actual.Path)
+	}
+}
+
+func testConcurrentPut(t *testing.T, s store.Store) {
+	const n = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- s.Put(fullAlias(fmt.Sprintf("proj%d", i)))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	aliases, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != n {
+		t.Errorf("Expected %d aliases after concurrent puts, but got %d", n, len(aliases))
+	}
+}
+
+func testList(t *testing.T, s store.Store) {
+	var expected []gopen.Alias
+	for _, name := range []string{"web", "api", "docs"} {
+		a := fullAlias(name)
+		expected = append(expected, a)
+
+		err := s.Put(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	actual, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := func(aliases []gopen.Alias) {
+		sort.Slice(aliases, func(i, j int) bool { return aliases[i].Alias < aliases[j].Alias })
+	}
+	byName(expected)
+	byName(actual)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected\n%+v\nbut got\n%+v", expected, actual)
+	}
+}
+
+func testDelete(t *testing.T, s store.Store) {
+	for _, name := range []string{"web", "api"} {
+		err := s.Put(fullAlias(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := s.Delete("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Get("web")
+	if !errors.Is(err, gopen.ErrNotFound) {
+		t.Errorf("Expected gopen.ErrNotFound after deleting, but got %v", err)
+	}
+	_, err = s.Get("api")
+	if err != nil {
+		t.Errorf("Expected the other alias to be kept, but got %v", err)
+	}
+
+	err = s.Delete("web")
+	if !errors.Is(err, gopen.ErrNotFound) {
+		t.Errorf("Expected gopen.ErrNotFound for deleting twice, but got %v", err)
+	}
+}
+
+func testWatch(t *testing.T, s store.Store) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := s.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := fullAlias("shop")
+	err = s.Put(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, events, store.Event{Op: store.OpPut, Alias: a})
+
+	err = s.Delete("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, events, store.Event{Op: store.OpDelete, Alias: gopen.Alias{Alias: "shop"}})
+
+	cancel()
+	select {
+	case ev, ok := <-events:
+		if ok {
+			t.Errorf("Expected no more events after cancelling, but got %+v", ev)
+		}
+	case <-time.After(Timeout):
+		t.Error("Expected the channel to be closed after cancelling")
+	}
+}
+
+func testWatchOrder(t *testing.T, s store.Store) {
+	// Fewer puts than the buffer of the Bolt watchers, which disconnect
+	// watchers that fall behind
+	const n = 50
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := s.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a := fullAlias("shop")
+			a.Path = fmt.Sprintf("/code/shop%d", i)
+			err := s.Put(a)
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	final, err := s.Get("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := store.Event{Op: store.OpPut, Alias: final}
+
+	// Events of earlier puts may come first, but none may follow the one of
+	// the put that was committed last
+	timeout := time.After(Timeout)
+	for seen := false; ; {
+		quiet := time.After(Timeout / 50)
+		if !seen {
+			quiet = nil
+		}
+
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatalf("Expected %+v, but the channel was closed", expected)
+			}
+			if seen {
+				t.Fatalf("Expected no events after the last put, but got %+v", ev)
+			}
+			seen = reflect.DeepEqual(ev, expected)
+		case <-quiet:
+			return
+		case <-timeout:
+			t.Fatalf("Expected %+v, but got nothing after %v", expected, Timeout)
+		}
+	}
+}
+
+func expectEvent(t *testing.T, events <-chan store.Event, expected store.Event) {
+	t.Helper()
+
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatalf("Expected %+v, but the channel was closed", expected)
+		}
+		if !reflect.DeepEqual(ev, expected) {
+			t.Errorf("Expected %+v, but got %+v", expected, ev)
+		}
+	case <-time.After(Timeout):
+		t.Fatalf("Expected %+v, but got nothing after %v", expected, Timeout)
+	}
+}
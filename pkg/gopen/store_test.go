@@ -0,0 +1,69 @@
+package gopen_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/waseem-medhat/gopen/pkg/gopen"
+)
+
+func TestStore(t *testing.T) {
+	store := gopen.NewStore(filepath.Join(t.TempDir(), "gopen", "gopen.json"))
+
+	_, err := store.Load()
+	if err == nil {
+		t.Error("Expected an error before the config is created, but got nil")
+	}
+
+	err = store.Init(gopen.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Init(gopen.Config{})
+	if !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected os.ErrExist, but got %v", err)
+	}
+
+	failed := errors.New("failed")
+	err = store.Update(func(cfg gopen.Config) (gopen.Config, error) {
+		cfg.EditorCmd = "nvim"
+		return cfg, failed
+	})
+	if err != failed {
+		t.Errorf("Expected the error of the update, but got %v", err)
+	}
+
+	cfg, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.EditorCmd != "" {
+		t.Errorf("Expected a failed update not to be saved, but got %+v", cfg)
+	}
+
+	_, err = store.Get("nope")
+	if !errors.Is(err, gopen.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, but got %v", err)
+	}
+}
+
+func TestReservedNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"sync", "a", "doctor"} {
+		_, err := gopen.Config{}.AddAlias(name, dir)
+		if err == nil {
+			t.Errorf("Expected an error for the reserved name %v, but got nil", name)
+		}
+	}
+
+	cfg, err := gopen.Config{}.AddAlias("proj", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.RenameAlias("proj", "status")
+	if err == nil {
+		t.Error("Expected an error when renaming to a reserved name, but got nil")
+	}
+}
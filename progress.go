@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// progress renders a combined progress display for commands that work on
+// many aliases concurrently. Every finished alias gets its own line, and when
+// stdout is a terminal, a status line listing the aliases still in progress is
+// redrawn below them.
+type progress struct {
+	mu     sync.Mutex
+	verb   string
+	tty    bool
+	total  int
+	done   int
+	active []string
+}
+
+func newProgress(verb string, total int) *progress {
+	stat, err := os.Stdout.Stat()
+	tty := err == nil && stat.Mode()&os.ModeCharDevice != 0
+
+	return &progress{verb: verb, tty: tty, total: total}
+}
+
+func (p *progress) start(alias string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active = append(p.active, alias)
+	p.redraw()
+}
+
+func (p *progress) finish(alias string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, a := range p.active {
+		if a == alias {
+			p.active = append(p.active[:i], p.active[i+1:]...)
+			break
+		}
+	}
+	p.done++
+
+	p.clearLine()
+	if err != nil {
+		fmt.Printf("[%d/%d] FAIL %v: %v\n", p.done, p.total, alias, err)
+	} else {
+		fmt.Printf("[%d/%d] ok   %v\n", p.done, p.total, alias)
+	}
+	p.redraw()
+}
+
+func (p *progress) redraw() {
+	if !p.tty || len(p.active) == 0 {
+		return
+	}
+
+	p.clearLine()
+	fmt.Printf("%v: %v", p.verb, strings.Join(p.active, ", "))
+}
+
+func (p *progress) clearLine() {
+	if p.tty {
+		fmt.Print("\r\033[K")
+	}
+}
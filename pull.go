@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/repo"
+)
+
+func handlePull() {
+	fs := flag.NewFlagSet("pull", flag.ExitOnError)
+	tag := fs.String("tag", "", "pull the projects tagged with this tag")
+	jobs := fs.Int("jobs", repo.DefaultJobs, "number of projects pulled at the same time")
+	aliases := parseArgs(fs, os.Args[2:])
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	selected, err := cfg.Select(aliases, *tag)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(selected) == 0 {
+		fmt.Println("No projects selected")
+		return
+	}
+
+	fmt.Printf("Pulling %d project(s)...\n\n", len(selected))
+	results := repo.Each(selected, *jobs, repo.Pull, nil)
+
+	failures := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALIAS\tRESULT\tDETAIL")
+	for _, res := range results {
+		if res.Outcome == repo.PullFailed {
+			failures++
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\n", res.Alias, res.Outcome, dash(res.Detail))
+	}
+	w.Flush()
+
+	if failures > 0 {
+		os.Exit(1)
+	}
+}
+
+func handleTag() {
+	fs := flag.NewFlagSet("tag", flag.ExitOnError)
+	clearTags := fs.Bool("clear", false, "remove all tags from the alias")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) == 0 {
+		fmt.Println("Error: must provide an alias to 'tag' command")
+		os.Exit(1)
+	}
+	alias := args[0]
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	if len(args) == 1 && !*clearTags {
+		selected, err := cfg.Select([]string{alias}, "")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, t := range selected[0].Tags {
+			fmt.Println(t)
+		}
+		return
+	}
+
+	saved := updateConfig(func(cfg config.C) (config.C, error) {
+		return cfg.SetTags(alias, args[1:])
+	})
+	if !saved {
+		os.Exit(1)
+	}
+}
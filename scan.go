@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/discover"
+	"github.com/waseem-medhat/gopen/internal/tui"
+)
+
+func handleScan() {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	depth := fs.Int("depth", 2, "how many directory levels below the root to search")
+	yes := fs.Bool("yes", false, "add all found projects without reviewing them")
+
+	args := parseArgs(fs, os.Args[2:])
+	if len(args) != 1 {
+		fmt.Println("Error: must provide one root directory to 'scan' command")
+		os.Exit(1)
+	}
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	picked, err := scanPick(cfg, args[0], *depth, *yes)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+	if len(picked) == 0 {
+		return
+	}
+
+	// The checklist can stay open for a while, so the picked aliases are added
+	// to the config as it is when saving rather than as it was when loaded.
+	var added int
+	err = store.Update(func(cfg config.C) (config.C, error) {
+		cfg, added = addScanned(cfg, picked)
+		return cfg, nil
+	})
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	fmt.Printf("Added %d alias(es)\n", added)
+}
+
+// scanInto searches root for projects and adds the ones picked in a checklist
+// (or all of them if yes is set) to cfg. It returns the new config and how
+// many aliases were added.
+func scanInto(cfg config.C, root string, depth int, yes bool) (config.C, int, error) {
+	picked, err := scanPick(cfg, root, depth, yes)
+	if err != nil || len(picked) == 0 {
+		return cfg, 0, err
+	}
+
+	cfg, added := addScanned(cfg, picked)
+	return cfg, added, nil
+}
+
+// scanPick searches root for projects that aren't aliased in cfg yet and
+// returns the ones picked in a checklist, or all of them if yes is set.
+func scanPick(cfg config.C, root string, depth int, yes bool) ([]config.DirAlias, error) {
+	projects, err := discover.Scan(root, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, items := scanCandidates(cfg, projects)
+	if len(items) == 0 {
+		fmt.Println("No new projects found")
+		return nil, nil
+	}
+
+	if !yes {
+		p := tui.StartChecklist("Which projects do you want to add?", items)
+		m, err := p.Run()
+		if err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
+			os.Exit(1)
+		}
+
+		checklist, ok := m.(tui.Checklist)
+		if !ok || !checklist.Confirmed {
+			fmt.Println("Cancelled - no aliases were added")
+			return nil, nil
+		}
+		items = checklist.Items
+	}
+
+	var picked []config.DirAlias
+	for i, item := range items {
+		if item.Checked {
+			picked = append(picked, aliases[i])
+		}
+	}
+	if len(picked) == 0 {
+		fmt.Println("No aliases were added")
+	}
+
+	return picked, nil
+}
+
+// addScanned adds the picked aliases to cfg, skipping (with a message) the
+// ones that can't be added, and returns the new config and how many were
+// added.
+func addScanned(cfg config.C, picked []config.DirAlias) (config.C, int) {
+	added := 0
+	for _, dirAlias := range picked {
+		var err error
+		cfg, err = cfg.AddAlias(dirAlias.Alias, dirAlias.Path)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		added++
+	}
+
+	return cfg, added
+}
+
+// scanCandidates suggests an alias for each project whose path isn't already
+// aliased, and returns them with matching (checked) checklist items.
+func scanCandidates(cfg config.C, projects []discover.Project) ([]config.DirAlias, []tui.ChecklistItem) {
+	var taken []string
+	aliasedPaths := map[string]bool{}
+	for _, dirAlias := range cfg.DirAliases {
+		taken = append(taken, dirAlias.Alias)
+		if !dirAlias.IsRemote() {
+			aliasedPaths[dirAlias.Path] = true
+		}
+	}
+
+	var aliases []config.DirAlias
+	var items []tui.ChecklistItem
+	for _, p := range projects {
+		if aliasedPaths[p.Path] {
+			continue
+		}
+
+		alias := config.SuggestAlias(p.Path, taken)
+		taken = append(taken, alias)
+
+		detail := p.Path + " (" + strings.Join(p.Markers, ", ") + ")"
+		if p.GitRepo != "" {
+			detail += " " + p.GitRepo
+		}
+
+		aliases = append(aliases, config.DirAlias{Alias: alias, Path: p.Path, GitRepo: p.GitRepo})
+		items = append(items, tui.ChecklistItem{Label: alias, Detail: detail, Checked: true})
+	}
+
+	return aliases, items
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/waseem-medhat/gopen/internal/repo"
+)
+
+func handleStatus() {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the statuses as JSON")
+	onlyDirty := fs.Bool("dirty", false, "only show projects with uncommitted changes")
+	onlyMissing := fs.Bool("missing", false, "only show projects whose path doesn't exist")
+	jobs := fs.Int("jobs", repo.DefaultJobs, "number of projects inspected at the same time")
+
+	args := parseArgs(fs, os.Args[2:])
+	if len(args) > 0 {
+		fmt.Println("Error: 'status' doesn't take any arguments")
+		os.Exit(1)
+	}
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	statuses := repo.Each(cfg.DirAliases, *jobs, repo.GetStatus, nil)
+
+	// --dirty and --missing together show projects matching either
+	var shown []repo.Status
+	for _, s := range statuses {
+		if (*onlyDirty || *onlyMissing) && !(*onlyDirty && s.Dirty || *onlyMissing && !s.Exists) {
+			continue
+		}
+		shown = append(shown, s)
+	}
+
+	if *asJSON {
+		if shown == nil {
+			shown = []repo.Status{}
+		}
+		out, err := json.MarshalIndent(shown, "", "  ")
+		if err != nil {
+			fmt.Println(fmt.Errorf("error: %v", err))
+			return
+		}
+		fmt.Println(string(out))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALIAS\tBRANCH\tUPSTREAM\tSTATE\tPATH")
+	for _, s := range shown {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", s.Alias, dash(s.Branch), formatSync(s), formatState(s), location(s))
+	}
+	w.Flush()
+}
+
+func formatSync(s repo.Status) string {
+	switch {
+	case s.Upstream == "":
+		return "-"
+	case s.Ahead == 0 && s.Behind == 0:
+		return "up to date"
+	case s.Behind == 0:
+		return fmt.Sprintf("ahead %d", s.Ahead)
+	case s.Ahead == 0:
+		return fmt.Sprintf("behind %d", s.Behind)
+	default:
+		return fmt.Sprintf("ahead %d, behind %d", s.Ahead, s.Behind)
+	}
+}
+
+func formatState(s repo.Status) string {
+	switch {
+	case s.Error != "":
+		return "error: " + s.Error
+	case !s.Exists:
+		return "missing"
+	case s.Host != "":
+		return "remote"
+	case !s.IsRepo:
+		return "not a repo"
+	case s.Dirty:
+		return "dirty"
+	default:
+		return "clean"
+	}
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func location(s repo.Status) string {
+	if s.Host != "" {
+		return s.Host + ":" + s.Path
+	}
+	return s.Path
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/waseem-medhat/gopen/internal/config"
+	"github.com/waseem-medhat/gopen/internal/repo"
+)
+
+func handleSync() {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "list the repos that would be cloned without cloning them")
+	jobs := fs.Int("jobs", repo.DefaultJobs, "number of repos cloned at the same time")
+
+	args := parseArgs(fs, os.Args[2:])
+	if len(args) > 0 {
+		fmt.Println("Error: 'sync' doesn't take any arguments")
+		os.Exit(1)
+	}
+
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error: %v", err))
+		return
+	}
+
+	missing, unreachable := cfg.MissingRepos()
+	for _, err := range unreachable {
+		fmt.Printf("Warning: skipping %v\n", err)
+	}
+
+	if len(missing) == 0 {
+		fmt.Println("All git-backed projects are already present")
+		return
+	}
+
+	if *dryRun {
+		fmt.Printf("Would clone %d repo(s):\n", len(missing))
+		for _, dirAlias := range missing {
+			fmt.Printf("  %v: %v -> %v\n", dirAlias.Alias, dirAlias.GitRepo, dirAlias.Location())
+		}
+		return
+	}
+
+	p := newProgress("cloning", len(missing))
+	errs := repo.Each(missing, *jobs, func(dirAlias config.DirAlias) error {
+		p.start(dirAlias.Alias)
+		return dirAlias.Clone(nil)
+	}, func(i int, err error) {
+		p.finish(missing[i].Alias, err)
+	})
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("  %v: %v", missing[i].Alias, err))
+		}
+	}
+
+	fmt.Printf("\nCloned %d of %d repo(s)\n", len(missing)-len(failed), len(missing))
+	if len(failed) > 0 {
+		fmt.Println("Failed:")
+		for _, f := range failed {
+			fmt.Println(f)
+		}
+		os.Exit(1)
+	}
+}